@@ -42,3 +42,7 @@ func Infof(format string, a ...any) {
 func Userf(format string, a ...any) {
 	userln(format, a...)
 }
+
+func Warnf(format string, a ...any) {
+	logln("⚠ "+format, a...)
+}
@@ -24,9 +24,18 @@ func mainE(args []string) error {
 	help := flag.Bool("help", false, "Show help screen")
 	outDir := flag.String("out", "./svg-scad", "Output directory for .scad files")
 	//watch := flag.Bool("watch", false, "watch for changes to the .svg files and refresh .scad files automatically")
-	flag.IntVar(&sw.SplineSteps, "detail", 32, "Higher values create smoother curves, excessive values may cause issues")
+	flag.Int("detail", 0, "Deprecated and ignored, use -tolerance instead")
+	tolerance := flag.String("tolerance", "0.01mm", "Maximum distance between a curve and the straight lines approximating it, smaller values give smoother curves")
+	flag.Float64Var(&sw.DPI, "dpi", svg.DefaultDPI, "Resolution for px and unitless sizes: 96 (CSS, Inkscape 0.92+), 90 (older Inkscape) or 72 (Illustrator)")
+	flag.IntVar(&sw.Precision, "precision", 4, "Number of decimal places in the generated coordinates, in millimetres")
 	flag.BoolVar(&log.Debug, "debug", false, "Print debug/tracing info, for development use")
 	flag.BoolVar(&log.Quiet, "quiet", false, "Quiet mode, don't print info messages, only errors")
+	flag.BoolVar(&sw.Pure, "pure", false, "Write plain OpenSCAD that doesn't need BOSL2, the shapes can't be attached")
+	flag.BoolVar(&sw.FlipY, "flip-y", true, "Flip the y axis to match OpenSCAD, SVG's y axis points down")
+	flag.StringVar(&sw.Origin, "origin", "center", "Point of each shape that is placed at [0,0]: svg to keep the SVG's origin, or center, left, right, top, bottom, top-left, top-right, bottom-left or bottom-right of its bounding box")
+	flag.BoolVar(&sw.IncludeHidden, "include-hidden", false, "Convert hidden paths, such as those in hidden layers, and the contents of <defs> too")
+	flag.StringVar(&sw.SplitBy, "split-by", "", "Add a module for each Inkscape layer with layer, or also write each top-level layer to its own .scad file with layer-file")
+	prefix := flag.String("prefix", "", "Prefix for every function and module name, so several converted files can be included together (default: the file name and _)")
 	flag.BoolVar(&sw.PrintExamples, "example", false, "Print an example showing how to use your shapes")
 
 	flag.CommandLine.Parse(args)
@@ -36,6 +45,41 @@ func mainE(args []string) error {
 		os.Exit(1)
 	}
 
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "prefix":
+			sw.Prefix = prefix // only when given, even if empty, since the default depends on the file
+		case "detail":
+			log.Warnf("-detail is deprecated and has no effect, use -tolerance to make curves smoother or coarser")
+		}
+	})
+
+	if sw.DPI <= 0 {
+		return fmt.Errorf("-dpi must be greater than zero, got %v", sw.DPI)
+	}
+
+	if _, ok := scad.Origins[sw.Origin]; !ok && sw.Origin != scad.SVGOrigin {
+		return fmt.Errorf("-origin must be %s or a point of the bounding box such as center or bottom-left, got %q", scad.SVGOrigin, sw.Origin)
+	}
+
+	if sw.SplitBy != "" && sw.SplitBy != scad.SplitLayer && sw.SplitBy != scad.SplitLayerFiles {
+		return fmt.Errorf("-split-by must be %s or %s, got %q", scad.SplitLayer, scad.SplitLayerFiles, sw.SplitBy)
+	}
+
+	if sw.Prefix != nil && scad.Identifier(*sw.Prefix+"_x") != *sw.Prefix+"_x" {
+		return fmt.Errorf("-prefix must be made of letters, digits and _, and not start with a digit, got %q", *sw.Prefix)
+	}
+	if sw.Prefix != nil && *sw.Prefix == "" && !sw.Pure {
+		log.Warnf("with an empty -prefix, a path named after one of BOSL2's functions or modules will replace it")
+	}
+
+	if err := parseTolerance(*tolerance, &sw); err != nil {
+		return err
+	}
+	if sw.Precision < 0 {
+		return fmt.Errorf("-precision must not be negative, got %d", sw.Precision)
+	}
+
 	svgFiles := flag.Args()
 
 	if len(svgFiles) == 0 {
@@ -49,7 +93,7 @@ func mainE(args []string) error {
 	}
 
 	for _, file := range svgFiles {
-		svg, err := svg.ReadSVGFromFile(file)
+		svg, err := svg.ReadSVGFromFile(file, sw.DPI)
 		if err != nil {
 			return fmt.Errorf("the SVG file %q could not be read: %w", file, err)
 		}
@@ -65,3 +109,19 @@ func mainE(args []string) error {
 
 	return nil
 }
+
+// parseTolerance sets the writer's tolerance from a length such as "0.01mm". Plain numbers are millimetres.
+func parseTolerance(tolerance string, sw *scad.SCADWriter) error {
+	l, err := svg.ParseLength(tolerance)
+	if err != nil || l.IsPercent() {
+		return fmt.Errorf("-tolerance must be a length such as 0.01mm, got %q", tolerance)
+	}
+	if l.Unit == "" {
+		l.Unit = "mm"
+	}
+	sw.Tolerance, err = l.Millimetres(sw.DPI)
+	if err != nil || sw.Tolerance <= 0 {
+		return fmt.Errorf("-tolerance must be greater than zero, got %q", tolerance)
+	}
+	return nil
+}
@@ -4,27 +4,32 @@ import "fmt"
 
 // Symbol/variable names within SCAD code
 const (
-	prefix  = "__s2s_" // Uniqifier for ensuring no name collisions with user-defined symbols
-	CURSOR  = "cursor" // cursor is always local scope and needs no prefix
-	EXTENTS = prefix + "extents"
+	prefix = "__s2s_" // Uniqifier for ensuring no name collisions with user-defined symbols
+	REGION = prefix + "region"
+	APPLY  = prefix + "apply"
 )
 
 const LibSubdir = "lib"
 const LibFilename = "svg2scad.scad"
 
-var Imports = []string{
-	"include <BOSL2/beziers.scad>",
-	"include <BOSL2/std.scad>",
-	fmt.Sprintf("include <%s/%s>", LibSubdir, LibFilename)}
+var BOSL2Imports = []string{"include <BOSL2/std.scad>"}
+
+var LibImport = fmt.Sprintf("include <%s/%s>", LibSubdir, LibFilename)
 
 var LibFileData = []byte(fmt.Sprintf(
 	`// CODEGEN: This file was GENERATED by svg2scad and SHOULD NOT BE MODIFIED by hand.
 
-// Finds the bounding box of a set of 2D coordinates
-function %[1]s(coords, largest = [ -1e9, -1e9 ], smallest = [ 1e9, 1e9 ]) =
-    len(coords) == 0 ? [ largest, smallest ]
-                     : %[1]s(list_tail(coords),
-                               [ max(largest[0],  coords[0][0]), max(largest[1],  coords[0][1]) ],
-                               [ min(smallest[0], coords[0][0]), min(smallest[1], coords[0][1]) ]);
+// This file only uses built-in OpenSCAD features, so it works with or without BOSL2.
+
+// Draws a list of polygons as a single polygon, where nested polygons are holes (the even-odd rule)
+module %[1]s(polygons)
+{
+    starts = [ for (i = 0, start = 0; i < len(polygons); start = start + len(polygons[i]), i = i + 1) start ];
+    polygon([ for (p = polygons) each p ],
+            [ for (i = [0:1:len(polygons) - 1]) [ for (j = [0:1:len(polygons[i]) - 1]) starts[i] + j ] ]);
+}
+
+// Applies a 2D affine transform, given as the top two rows of a 3x3 matrix, to every point of a region
+function %[2]s(m, region) = [ for (path = region) [ for (p = path) m * [ p[0], p[1], 1 ] ] ];
 
-`, EXTENTS))
+`, REGION, APPLY))
@@ -0,0 +1,92 @@
+package scad
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mattolenik/svg2scad/log"
+)
+
+// reservedNames can't be used for generated functions and modules. They are OpenSCAD's keywords, constants and
+// built-in functions and modules, which a definition of the same name would replace, including for the generated
+// code and BOSL2, plus the BOSL2 functions and modules the generated code calls.
+var reservedNames = map[string]bool{
+	// Keywords and constants
+	"module": true, "function": true, "if": true, "else": true, "for": true, "intersection_for": true,
+	"let": true, "each": true, "assert": true, "echo": true, "include": true, "use": true,
+	"true": true, "false": true, "undef": true, "PI": true,
+	// Built-in functions
+	"abs": true, "acos": true, "asin": true, "atan": true, "atan2": true, "ceil": true, "chr": true,
+	"concat": true, "cos": true, "cross": true, "exp": true, "floor": true, "is_bool": true,
+	"is_function": true, "is_list": true, "is_num": true, "is_object": true, "is_string": true,
+	"is_undef": true, "len": true, "ln": true, "log": true, "lookup": true, "max": true, "min": true,
+	"norm": true, "object": true, "ord": true, "pow": true, "rands": true, "round": true, "search": true,
+	"sign": true, "sin": true, "sqrt": true, "str": true, "tan": true, "version": true, "version_num": true,
+	"parent_module": true, "textmetrics": true, "fontmetrics": true, "dxf_dim": true, "dxf_cross": true,
+	// Built-in modules
+	"circle": true, "square": true, "polygon": true, "text": true, "import": true, "projection": true,
+	"sphere": true, "cube": true, "cylinder": true, "polyhedron": true, "surface": true,
+	"linear_extrude": true, "rotate_extrude": true, "roof": true, "translate": true, "rotate": true,
+	"scale": true, "resize": true, "mirror": true, "multmatrix": true, "color": true, "offset": true,
+	"hull": true, "minkowski": true, "fill": true, "union": true, "difference": true, "intersection": true,
+	"render": true, "children": true, "group": true, "child": true,
+	// BOSL2, called by the generated code
+	"region": true, "make_region": true, "attachable": true,
+	REGION: true, APPLY: true,
+}
+
+var invalidIdentifierRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// Identifier turns any string into a valid OpenSCAD identifier. Runs of characters that can't be part of one are
+// replaced with an underscore, one is added in front of a leading digit, and reserved names get one at the end.
+// The result is empty only if s is.
+func Identifier(s string) string {
+	name := invalidIdentifierRegex.ReplaceAllString(s, "_")
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	if reservedNames[name] {
+		name += "_"
+	}
+	return name
+}
+
+// Namer gives out unique identifiers for the functions and modules of one output file, all starting with prefix.
+type Namer struct {
+	prefix string
+	used   map[string]bool
+}
+
+func NewNamer(prefix string) *Namer {
+	return &Namer{prefix: prefix, used: map[string]bool{}}
+}
+
+// usable reports whether name has any letters or digits, which an identifier can be made from.
+func usable(name string) bool {
+	return strings.Trim(Identifier(name), "_") != ""
+}
+
+// Name returns a unique identifier for an element named name in the SVG, and logs a warning if it had to be
+// changed other than by adding the prefix. If name has nothing usable in it, e.g. it is empty, fallback is used
+// instead, and if that has nothing usable either, a generic name. The result is never just the prefix.
+func (n *Namer) Name(name, fallback string) string {
+	base := name
+	if !usable(base) {
+		base = fallback
+	}
+	if !usable(base) {
+		base = "shape"
+	}
+	id := Identifier(n.prefix + base)
+	unique := id
+	for i := 2; n.used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", id, i)
+	}
+	n.used[unique] = true
+	if name != "" && unique != n.prefix+name {
+		log.Warnf("renamed %q to %s, to make it a valid and unique OpenSCAD name", name, unique)
+	}
+	return unique
+}
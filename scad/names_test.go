@@ -0,0 +1,73 @@
+package scad
+
+import "testing"
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"logo", "logo"},
+		{"Logo_2", "Logo_2"},
+		{"my logo", "my_logo"},
+		{"cls-1", "cls_1"},
+		{"a -- b", "a_b"},
+		{"2nd", "_2nd"},
+		{"1", "_1"},
+		{"module", "module_"},
+		{"use", "use_"},
+		{"len", "len_"},
+		{"square", "square_"},
+		{"make_region", "make_region_"},
+		{REGION, REGION + "_"},
+		{"lengths", "lengths"},
+		{`x() = 1; echo("pwned"); function y`, "x_1_echo_pwned_function_y"},
+		{"a\nb", "a_b"},
+		{"✓", "_"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Identifier(tt.name); got != tt.want {
+				t.Errorf("Identifier(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamer(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		calls  [][2]string // the name and fallback passed to each call of Name
+		want   []string
+	}{
+		{"prefix added", "logo_", [][2]string{{"outline", ""}}, []string{"logo_outline"}},
+		{"no prefix", "", [][2]string{{"outline", ""}}, []string{"outline"}},
+		{"duplicates numbered", "", [][2]string{{"a", ""}, {"a", ""}, {"a", ""}},
+			[]string{"a", "a_2", "a_3"}},
+		{"sanitized names can collide", "", [][2]string{{"a b", ""}, {"a-b", ""}}, []string{"a_b", "a_b_2"}},
+		{"empty name uses the fallback", "p_", [][2]string{{"", "path_1"}}, []string{"p_path_1"}},
+		{"all invalid name uses the fallback", "", [][2]string{{"✓", "path_1"}, {"-", "path_2"}},
+			[]string{"path_1", "path_2"}},
+		{"all invalid name with no fallback", "", [][2]string{{"✓", ""}, {"", ""}}, []string{"shape", "shape_2"}},
+		{"never only the prefix", "c_", [][2]string{{"✓", ""}, {"-", "_"}}, []string{"c_shape", "c_shape_2"}},
+		{"keyword without a prefix", "", [][2]string{{"module", ""}}, []string{"module_"}},
+		{"keyword with a prefix", "my_", [][2]string{{"module", ""}}, []string{"my_module"}},
+		{"built-in without a prefix", "", [][2]string{{"len", ""}, {"len", ""}}, []string{"len_", "len__2"}},
+		{"prefix and name make a built-in", "ma", [][2]string{{"x", ""}}, []string{"max_"}},
+		{"leading digit", "", [][2]string{{"3d", ""}}, []string{"_3d"}},
+		{"injection", "", [][2]string{{`a() = 0; } module b() { cube(1); } function c`, ""}},
+			[]string{"a_0_module_b_cube_1_function_c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNamer(tt.prefix)
+			for i, call := range tt.calls {
+				if got := n.Name(call[0], call[1]); got != tt.want[i] {
+					t.Errorf("Name(%q, %q) = %q, want %q", call[0], call[1], got, tt.want[i])
+				}
+			}
+		})
+	}
+}
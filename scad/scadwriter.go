@@ -10,28 +10,81 @@ import (
 
 	"github.com/mattolenik/svg2scad/files"
 	"github.com/mattolenik/svg2scad/log"
+	"github.com/mattolenik/svg2scad/std"
 	"github.com/mattolenik/svg2scad/svg"
 	"github.com/mattolenik/svg2scad/svg/ast"
 )
 
 type SCADWriter struct {
-	SplineSteps   int
+	Tolerance     float64 // maximum distance in millimetres between a curve and the lines approximating it
 	PrintExamples bool
-}
+	Pure          bool    // write plain OpenSCAD that doesn't need BOSL2, without attachment support
+	FlipY         bool    // flip the y axis, since SVG's points down and OpenSCAD's points up
+	Origin        string  // which point of a shape ends up at [0,0], see Origins
+	DPI           float64 // resolution used to convert px and unitless lengths to millimetres
+	Precision     int     // number of decimal places in the generated coordinates
+	IncludeHidden bool    // convert hidden paths and the contents of <defs> too
+	SplitBy       string  // SplitLayer or SplitLayerFiles to add modules or files for parts of the drawing
+	Prefix        *string // added to every function and module name, or nil to derive one from each file's name
 
-func id(p *int) int {
-	*p++
-	return *p
+	defined map[string]string // names written so far in this run, to the file they are in
 }
 
+// SplitBy values, which add modules or files for parts of a drawing.
+const (
+	SplitLayer      = "layer"      // a module for each Inkscape layer
+	SplitLayerFiles = "layer-file" // a .scad file for each top-level Inkscape layer, and a module for each layer
+)
+
 func (sw *SCADWriter) ConvertSVG(svg *svg.SVG, outDir, filename string) error {
-	outPath := filepath.Join(outDir, filename)
-	writer, err := os.Create(outPath)
+	doc, err := sw.newDocument(svg)
 	if err != nil {
-		return fmt.Errorf("couldn't create output .scad file %q: %w", outPath, err)
+		return err
 	}
-	defer writer.Close()
-	err = sw.ConvertSVGToSCAD(svg, writer, outPath)
+	main := part{definitions: true, all: true}
+	if sw.SplitBy == SplitLayerFiles {
+		base := strings.TrimSuffix(filename, filepath.Ext(filename))
+		used := map[string]bool{filename: true}
+		if len(doc.definitions) > 0 {
+			// Symbols and the other elements that <use> refers to go in a file of their own, which the layer
+			// files and the main file all include
+			defsFile := base + "_symbols.scad"
+			used[defsFile] = true
+			log.Userf("symbols → %s", filepath.Join(outDir, defsFile))
+			err := sw.writeFile(filepath.Join(outDir, defsFile), func(output io.Writer, outPath string) error {
+				return sw.writeSCAD(doc, output, outPath, part{definitions: true})
+			})
+			if err != nil {
+				return err
+			}
+			main.includes = append(main.includes, defsFile)
+			main.definitions = false
+		}
+		// Each top-level layer gets its own file, which the main file includes, e.g. logo_cut.scad for the
+		// layer "cut" in logo.svg
+		layerFiles := []string{}
+		for i, layer := range doc.layers {
+			layerFile := fmt.Sprintf("%s_%s.scad", base, Identifier(layer.Name()))
+			if Identifier(layer.Name()) == "" || used[layerFile] {
+				layerFile = fmt.Sprintf("%s_layer_%d.scad", base, i+1)
+			}
+			used[layerFile] = true
+			log.Userf("layer %q → %s", layer.Name(), filepath.Join(outDir, layerFile))
+			paths, uses := doc.contents(layer)
+			p := part{includes: main.includes, paths: paths, uses: uses, layers: doc.withSublayers(layer)}
+			err := sw.writeFile(filepath.Join(outDir, layerFile), func(output io.Writer, outPath string) error {
+				return sw.writeSCAD(doc, output, outPath, p)
+			})
+			if err != nil {
+				return err
+			}
+			layerFiles = append(layerFiles, layerFile)
+		}
+		main.includes = append(main.includes, layerFiles...)
+	}
+	err = sw.writeFile(filepath.Join(outDir, filename), func(output io.Writer, outPath string) error {
+		return sw.writeSCAD(doc, output, outPath, doc.mainPart(main))
+	})
 	if err != nil {
 		return err
 	}
@@ -42,94 +95,431 @@ func (sw *SCADWriter) ConvertSVG(svg *svg.SVG, outDir, filename string) error {
 	return nil
 }
 
+// writeFile creates the file at outPath and writes it with write.
+func (sw *SCADWriter) writeFile(outPath string, write func(output io.Writer, outPath string) error) error {
+	writer, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("couldn't create output .scad file %q: %w", outPath, err)
+	}
+	defer writer.Close()
+	return write(writer, outPath)
+}
+
+// ConvertSVGToSCAD writes the whole drawing to a single file. With SplitLayerFiles, the layers get modules but
+// no files of their own.
 func (sw *SCADWriter) ConvertSVGToSCAD(svg *svg.SVG, output io.Writer, outPath string) error {
-	cw := ast.NewCodeWriter()
-	cw.Lines(Imports...)
-	cw.BlankLine()
+	doc, err := sw.newDocument(svg)
+	if err != nil {
+		return err
+	}
+	return sw.writeSCAD(doc, output, outPath, doc.mainPart(part{definitions: true, all: true}))
+}
 
-	pathNames := []string{}
+// document holds the state of converting one SVG, which may be written to several files.
+type document struct {
+	svg         *svg.SVG
+	viewport    svg.Matrix // maps the root element's user units to output space
+	base        svg.Matrix // maps user units to the space definitions are written in, see definition
+	prefix      string
+	names       *Namer
+	unnamed     map[string]int           // count of paths without a name, per element name
+	shapes      map[*svg.Element][]shape // the shapes converted from each path and <use>
+	layers      []*svg.Group             // the top-level Inkscape layers, if split by layer
+	paths       []*svg.Path              // every path that is converted, in document order
+	uses        []*svg.Use               // every <use> that is converted, in document order
+	definitions []*definition            // the symbols and the elements <use> refers to
+	defined     map[*svg.Definition]*definition
+	hidden      bool // whether hidden paths are converted
+}
+
+func (sw *SCADWriter) newDocument(s *svg.SVG) (*document, error) {
+	viewport, err := s.ViewportTransform(sw.DPI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map the SVG viewport to millimetres: %w", err)
+	}
+	if sw.FlipY {
+		viewport = flipY.Multiply(viewport)
+	}
+	prefix := filePrefix(s.Filename)
+	if sw.Prefix != nil {
+		prefix = *sw.Prefix
+	}
+	doc := &document{
+		svg:      s,
+		viewport: viewport,
+		base:     viewport.Linear(),
+		prefix:   prefix,
+		names:    NewNamer(prefix),
+		unnamed:  map[string]int{},
+		shapes:   map[*svg.Element][]shape{},
+		defined:  map[*svg.Definition]*definition{},
+		hidden:   sw.IncludeHidden,
+	}
+	doc.paths, doc.uses = doc.contents(&s.Group)
+	if sw.SplitBy != "" {
+		doc.layers = s.Layers(sw.IncludeHidden)
+	}
+	doc.findDefinitions()
+	doc.measureScales()
+	return doc, nil
+}
+
+// contents returns the paths and <use> elements in the group that are converted.
+func (doc *document) contents(g *svg.Group) ([]*svg.Path, []*svg.Use) {
+	return g.Contents(doc.hidden)
+}
+
+// elements returns the elements in the group that are converted, paths first.
+func (doc *document) elements(g *svg.Group) []*svg.Element {
+	paths, uses := doc.contents(g)
+	return elements(paths, uses)
+}
+
+func elements(paths []*svg.Path, uses []*svg.Use) []*svg.Element {
+	elems := std.Map(paths, func(p *svg.Path) *svg.Element { return &p.Element })
+	return append(elems, std.Map(uses, func(u *svg.Use) *svg.Element { return &u.Element })...)
+}
+
+// withSublayers returns the layer followed by the layers nested in it, at any depth, in document order.
+func (doc *document) withSublayers(layer *svg.Group) []*svg.Group {
+	layers := []*svg.Group{layer}
+	for _, sub := range layer.Layers(doc.hidden) {
+		layers = append(layers, doc.withSublayers(sub)...)
+	}
+	return layers
+}
 
-	ids := map[string]int{} // for tracking path IDs in the loop below
-	uniq := 0
-
-	for _, path := range svg.Paths {
-		if path.ID == "" {
-			// Give unnamed paths a default name
-			path.ID = fmt.Sprintf("path_%d", id(&uniq))
-		} else {
-			// Make sure path.ID is fully unique. There shouldn't be multiple <path> elements in the
-			// SVG with the same ID, but if there are, they will be renamed with a numerical suffix.
-			count, exists := ids[path.ID]
-			if !exists {
-				ids[path.ID] = 1
-			} else {
-				ids[path.ID] = count + 1
-				path.ID = fmt.Sprintf("%s_%d", path.ID, ids[path.ID])
+// part is what to write to one .scad file.
+type part struct {
+	includes    []string
+	definitions bool // write the functions for the definitions, and modules for the symbols
+	paths       []*svg.Path
+	uses        []*svg.Use
+	layers      []*svg.Group
+	all         bool // write the module for the whole drawing
+}
+
+// mainPart fills in the contents of the main file for the drawing: the paths and uses that aren't written to
+// one of the included layer files, and the layer modules if split by layer.
+func (doc *document) mainPart(p part) part {
+	p.paths, p.uses = doc.paths, doc.uses
+	if len(p.includes) > 0 && len(doc.layers) > 0 {
+		inLayer := map[*svg.Element]bool{}
+		for _, layer := range doc.layers {
+			for _, elem := range doc.elements(layer) {
+				inLayer[elem] = true
 			}
 		}
-		tree, err := ast.Parse(path.ID, []byte(path.D))
-		if err != nil {
-			return fmt.Errorf("failed to parse path from SVG %q: %w", path, err)
+		p.paths = std.Filter(p.paths, func(path *svg.Path) bool { return !inLayer[&path.Element] })
+		p.uses = std.Filter(p.uses, func(use *svg.Use) bool { return !inLayer[&use.Element] })
+	} else {
+		for _, layer := range doc.layers {
+			p.layers = append(p.layers, doc.withSublayers(layer)...)
 		}
+	}
+	return p
+}
+
+// writeSCAD writes a .scad file with the given part of the drawing. Its contents are written in this order:
+// definitions, path functions, path modules, symbol modules, use modules, layer modules and the whole drawing.
+// Everything the layers draw must be converted in this file or in one of the included ones.
+func (sw *SCADWriter) writeSCAD(doc *document, output io.Writer, outPath string, p part) error {
+	cw := ast.NewCodeWriter()
+	if !sw.Pure {
+		cw.Lines(BOSL2Imports...)
+	}
+	cw.Lines(LibImport)
+	for _, include := range p.includes {
+		cw.Linef("include <%s>", include)
+	}
+	cw.BlankLine()
 
-		state := walkState{
-			paths:  []string{},
-			pathID: path.ID,
+	shapes := []shape{}
+	if p.definitions {
+		if err := sw.writeDefinitions(cw, doc); err != nil {
+			return err
+		}
+	}
+	for _, path := range p.paths {
+		// Give unnamed paths a default name after their element, e.g. path_1 or rect_2
+		fallback := ""
+		if !usable(path.Name()) {
+			doc.unnamed[path.XMLName.Local]++
+			fallback = fmt.Sprintf("%s_%d", path.XMLName.Local, doc.unnamed[path.XMLName.Local])
 		}
-		_, err = sw.walk(cw, tree, &state)
+		name := doc.names.Name(path.Name(), fallback)
+		state, err := sw.convertPath(cw, path, name, doc.viewport.Multiply(path.CTM), sw.Tolerance)
 		if err != nil {
-			return fmt.Errorf("failed to generate OpenSCAD code: %w", err)
+			return err
+		}
+		for _, name := range state.paths {
+			s := shape{name: name, bounds: state.bounds, regions: []string{name + "()"}}
+			shapes = append(shapes, s)
+			doc.shapes[&path.Element] = append(doc.shapes[&path.Element], s)
 		}
-		pathNames = append(pathNames, state.paths...)
 	}
 	cw.BlankLine()
-	for _, name := range pathNames {
-		cw.BlankLine()
-		cw.Linef("module %s(depth=0, anchor, spin, orient)", name)
-		cw.OpenBrace().Linef(
-			"p = %s([ 0, 0 ]);", name).Linef(
-			"exts = %s(p);", EXTENTS).Lines(
-			"width = exts[0][0] - exts[1][0];",
-			"height = exts[0][1] - exts[1][1];",
-			"two_d = depth == 0;",
-			"size = two_d ? [ width, height ] : [ width, height, depth ];",
-			"attachable(anchor, spin, orient, two_d = two_d, size = size)").
-			OpenBrace().
-			Lines(
-				"translate(-[ width / 2 + exts[1][0], height / 2 + exts[1][1], depth / 2 ])",
-				"if (!two_d) { linear_extrude(depth) polygon(p); } else { polygon(p); }",
-				"children();",
-			).
-			CloseBrace()
-		cw.CloseBrace()
+	if p.definitions {
+		shapes = append(shapes, sw.symbolShapes(doc)...)
+	}
+	for _, use := range p.uses {
+		if s, ok := sw.useShape(doc, use); ok {
+			shapes = append(shapes, s)
+			doc.shapes[&use.Element] = []shape{s}
+		}
+	}
+	for i, layer := range p.layers {
+		// The layer's contents where they are in the SVG, like the whole drawing below
+		fallback := fmt.Sprintf("layer_%d", i+1)
+		if l := doc.combine(doc.elements(layer), layer.Name(), fallback); len(l.regions) > 0 {
+			shapes = append(shapes, l)
+		}
+	}
+	if p.all {
+		// The whole drawing, with every path where it is in the SVG
+		composite := "all"
+		if doc.prefix == "" {
+			composite = compositeName(doc.svg.Filename)
+		}
+		if a := doc.combine(elements(doc.paths, doc.uses), composite, ""); len(a.regions) > 0 {
+			shapes = append(shapes, a)
+		}
+	}
+	pathNames := []string{}
+	for _, s := range shapes {
+		sw.writeShapeModule(cw, s)
+		pathNames = append(pathNames, s.name)
 	}
 	log.Userf("curves: %s", strings.Join(pathNames, ", "))
-	if sw.PrintExamples {
+	sw.checkDefined(pathNames, outPath)
+	if sw.PrintExamples && p.all && len(pathNames) > 0 {
+		first := pathNames[0]
+		for _, elem := range elements(doc.paths, doc.uses) {
+			if len(doc.shapes[elem]) > 0 {
+				first = doc.shapes[elem][0].name
+				break
+			}
+		}
 		log.Userf("\n  Usage, assuming your .scad file is in the current folder:\n")
 		log.Userf("  include <%s>", outPath)
-		log.Userf("  %s(100);  // get a 3D object, your path extruded by 100mm", pathNames[0])
-		log.Userf("  %s();     // get a 2D path", pathNames[0])
+		log.Userf("  %s(100);  // get a 3D object, your path extruded by 100mm", first)
+		log.Userf("  %s();     // get a 2D shape", first)
+		log.Userf("  %s();     // get the whole drawing, with every path in place", pathNames[len(pathNames)-1])
 		log.Userf("")
 	}
 	return cw.Write(output)
 }
 
-type walkState struct {
-	paths  []string
-	pathID string
-	points []ast.Coord
+// convertPath writes the function for a path, with its points transformed by ctm and its curves flattened to
+// the given tolerance. A path that can't be parsed, or encloses no area, e.g. a single straight line, is skipped,
+// and the state it returns has no paths.
+func (sw *SCADWriter) convertPath(cw *ast.CodeWriter, path *svg.Path, name string, ctm svg.Matrix, tolerance float64) (*walkState, error) {
+	state := newWalkState(name, ctm, path.Computed.FillRule)
+	state.tolerance = tolerance
+	tree, err := path.Parse()
+	if err != nil {
+		log.Warnf("skipping path %q, its path data can't be parsed: %v", path.ID, err)
+		return state, nil
+	}
+	if tree == nil {
+		return state, nil
+	}
+	function := ast.NewCodeWriter()
+	if _, err = sw.walk(function, tree, state); err != nil {
+		return nil, fmt.Errorf("failed to generate OpenSCAD code: %w", err)
+	}
+	if state.bounds.IsEmpty() {
+		log.Infof("skipping %s %q, it has no area", path.XMLName.Local, path.ID)
+		state.paths = nil
+		return state, nil
+	}
+	cw.Append(function)
+	return state, nil
+}
+
+// combine returns a shape that draws every one of the elements where it is in the SVG. It has no regions if
+// none of the elements have been converted.
+func (doc *document) combine(elems []*svg.Element, name, fallback string) shape {
+	combined := shape{bounds: ast.EmptyBounds()}
+	for _, elem := range elems {
+		for _, s := range doc.shapes[elem] {
+			combined.bounds = combined.bounds.Union(s.bounds)
+			combined.regions = append(combined.regions, s.regions...)
+		}
+	}
+	if len(combined.regions) > 0 {
+		combined.name = doc.names.Name(name, fallback)
+	}
+	return combined
 }
 
-func (ws *walkState) addPoint(p ast.Coord) {
-	ws.points = append(ws.points, p)
+// shape is a module to write, which draws one or more regions.
+type shape struct {
+	name    string
+	bounds  ast.Bounds // in output space
+	regions []string   // expressions that give a region, e.g. calls to the path functions
 }
 
-func (ws *walkState) firstPoint() ast.Coord {
-	return ws.points[0]
+// filePrefix returns the default prefix for the names in a file, from the file's name.
+func filePrefix(filename string) string {
+	return Identifier(strings.TrimSuffix(filename, filepath.Ext(filename)) + "_")
 }
 
-func (ws *walkState) lastPoint() ast.Coord {
-	return ws.points[len(ws.points)-1]
+// compositeName returns the name of the module for a whole drawing when there is no prefix.
+func compositeName(filename string) string {
+	return filePrefix(filename) + "all"
+}
+
+// checkDefined warns about names that another file converted in this run defines too. OpenSCAD silently uses
+// the last definition when both files are included.
+func (sw *SCADWriter) checkDefined(names []string, outPath string) {
+	if sw.defined == nil {
+		sw.defined = map[string]string{}
+	}
+	for _, name := range names {
+		if other, ok := sw.defined[name]; ok && other != outPath {
+			log.Warnf("%s is defined in both %s and %s, so they can't be included together, see -prefix", name, other, outPath)
+			continue
+		}
+		sw.defined[name] = outPath
+	}
+}
+
+func (sw *SCADWriter) writeShapeModule(cw *ast.CodeWriter, s shape) {
+	cw.BlankLine()
+	if sw.Pure {
+		sw.writePureModule(cw, s)
+	} else {
+		sw.writeModule(cw, s)
+	}
+}
+
+// maxLineLength is the length above which lists are written one item per line.
+const maxLineLength = 120
+
+// writeRegions writes the list of regions the shape draws.
+func (sw *SCADWriter) writeRegions(cw *ast.CodeWriter, s shape) {
+	line := fmt.Sprintf("regions = [ %s ];", strings.Join(s.regions, ", "))
+	if len(line) <= maxLineLength {
+		cw.Lines(line)
+		return
+	}
+	cw.Lines("regions = [").Indent()
+	for _, region := range s.regions {
+		cw.Lines(region + ",")
+	}
+	cw.Unindent().Lines("];")
+}
+
+// Origins maps the values of the Origin option to the point of a shape's bounding box that is moved to [0,0],
+// as a fraction of its width and height from the bottom left corner. "svg" isn't listed, it keeps the SVG's
+// own origin.
+var Origins = map[string][2]float64{
+	"center":       {0.5, 0.5},
+	"left":         {0, 0.5},
+	"right":        {1, 0.5},
+	"top":          {0.5, 1},
+	"bottom":       {0.5, 0},
+	"top-left":     {0, 1},
+	"top-right":    {1, 1},
+	"bottom-left":  {0, 0},
+	"bottom-right": {1, 0},
+}
+
+// SVGOrigin is the Origin value that keeps the SVG's own origin.
+const SVGOrigin = "svg"
+
+// flipY mirrors the y axis, since SVG's points down and OpenSCAD's points up.
+var flipY = svg.Matrix{1, 0, 0, -1, 0, 0}
+
+// origin returns the point that the Origin option moves to [0,0], in output space.
+func (sw *SCADWriter) origin(bounds ast.Bounds) ast.Coord {
+	fraction, ok := Origins[sw.Origin]
+	if !ok || bounds.IsEmpty() {
+		return ast.NewCoord(0, 0) // SVGOrigin
+	}
+	size := bounds.Size()
+	return ast.NewCoord(bounds.Min[0]+fraction[0]*size[0], bounds.Min[1]+fraction[1]*size[1])
+}
+
+// writeBounds writes the shape's bounding box, and the point that is moved to [0,0], as constants.
+func (sw *SCADWriter) writeBounds(cw *ast.CodeWriter, s shape) {
+	lower := s.bounds.Min
+	if s.bounds.IsEmpty() {
+		lower = ast.NewCoord(0, 0)
+	}
+	size := s.bounds.Size()
+	cw.Linef("bounds_min = %s;", lower.Columnized([2]int{}, sw.Precision))
+	cw.Linef("width = %s;", ast.FormatNumber(size[0], sw.Precision))
+	cw.Linef("height = %s;", ast.FormatNumber(size[1], sw.Precision))
+	cw.Linef("origin = %s;", sw.origin(s.bounds).Columnized([2]int{}, sw.Precision))
+}
+
+// writeModule writes the module for a shape, which is attachable using BOSL2.
+func (sw *SCADWriter) writeModule(cw *ast.CodeWriter, s shape) {
+	cw.Linef("module %s(depth=0, anchor, spin, orient)", s.name)
+	cw.OpenBrace()
+	sw.writeRegions(cw, s)
+	sw.writeBounds(cw, s)
+	cw.Lines(
+		"two_d = depth == 0;",
+		"size = two_d ? [ width, height ] : [ width, height, depth ];",
+		"// Anchors are relative to the center of the bounding box, wherever the origin is",
+		"center = [ bounds_min[0] + width / 2 - origin[0], bounds_min[1] + height / 2 - origin[1], 0 ];",
+		"attachable(anchor, spin, orient, two_d = two_d, size = size, offset = center)").
+		OpenBrace().
+		Lines(
+			"translate(-[ origin[0], origin[1], depth / 2 ])",
+			"if (!two_d) { linear_extrude(depth) for (r = regions) region(r); } else { for (r = regions) region(r); }",
+			"children();",
+		).
+		CloseBrace()
+	cw.CloseBrace()
+}
+
+// writePureModule writes the module for a shape using only built-in OpenSCAD. It is placed the same way as the
+// BOSL2 module, but can't be attached.
+func (sw *SCADWriter) writePureModule(cw *ast.CodeWriter, s shape) {
+	cw.Linef("module %s(depth=0)", s.name)
+	cw.OpenBrace()
+	sw.writeRegions(cw, s)
+	sw.writeBounds(cw, s)
+	cw.Lines("translate(-[ origin[0], origin[1], depth / 2 ])").Linef(
+		"if (depth > 0) { linear_extrude(depth) for (r = regions) %[1]s(r); } else { for (r = regions) %[1]s(r); }", REGION)
+	cw.CloseBrace()
+}
+
+// walkState tracks the current point, subpath and last control point of a path, as the SVG spec defines them.
+// All points are in the path's own user space, before ctm is applied.
+type walkState struct {
+	paths        []string
+	bounds       ast.Bounds // of the path's filled area, in output space
+	name         string     // of the path's function
+	ctm          svg.Matrix // maps the path's user space to millimetres
+	current      ast.Coord  // the current point, where the next command starts
+	subpathStart ast.Coord  // the start of the current subpath, where ClosePath returns to
+	lastCommand  any        // the previous command in the path
+	lastControl  ast.Coord  // the last control point of lastCommand, if it was a curve
+	fillRule     svg.FillRule
+	tolerance    float64 // for flattening curves, in output space
+}
+
+func newWalkState(name string, ctm svg.Matrix, fillRule svg.FillRule) *walkState {
+	origin := ast.NewCoord(0, 0)
+	return &walkState{
+		paths:        []string{},
+		bounds:       ast.EmptyBounds(),
+		name:         name,
+		ctm:          ctm,
+		fillRule:     fillRule,
+		current:      origin,
+		subpathStart: origin,
+	}
+}
+
+// transform maps coordinates from the path's user space to the output space.
+func (ws *walkState) transform(c ast.Coords) ast.Coords {
+	return c.Map(ws.ctm.Apply)
 }
 
 func (sw *SCADWriter) walk(cw *ast.CodeWriter, node any, state *walkState) (val any, err error) {
@@ -138,70 +528,107 @@ func (sw *SCADWriter) walk(cw *ast.CodeWriter, node any, state *walkState) (val
 
 	case *ast.MoveTo:
 		if node.Relative {
-			node.Coord = node.Coord.Add(state.lastPoint())
+			node.Coord = node.Coord.Add(state.current)
 		}
-		cw.Linef("let(%s = %s + %v)", ast.Cursor, ast.Cursor, node.Coord)
-		state.addPoint(node.Coord)
+		state.current, state.subpathStart = node.Coord, node.Coord
 		return nil, nil
 
 	case ast.CommandList:
-		curveCoords := []ast.Coords{}
-		for _, child := range node {
-			r, err := sw.walk(cw, child, state)
-			if err != nil {
-				return nil, fmt.Errorf("failed building curve: %w", err)
-			}
-			if r == nil {
-				continue
-			}
-			switch r := r.(type) {
-			case ast.Coords:
-				curveCoords = append(curveCoords, r)
-				state.addPoint(r[len(r)-1])
-			default:
-				return nil, fmt.Errorf("type %v is not supported", reflect.TypeOf(r))
-			}
-		}
-		cw.Linef("let(curve = [ %s, ", ast.Cursor)
-		cw.Indent()
-		colWidths := make([][2]int, 3)
-		for i, ws := range colWidths {
-			for c := range ws {
-				for _, r := range curveCoords {
-					if len(r[i][c]) > colWidths[i][c] {
-						colWidths[i][c] = len(r[i][c])
-					}
-				}
-			}
-		}
-		for _, coord := range curveCoords {
-			cw.Lines(coord.Columnized(colWidths) + ",")
+		subpaths, err := sw.walkSubpaths(cw, node, state)
+		if err != nil {
+			return nil, err
 		}
-		cw.Unindent()
-		cw.Lines("],")
-
-		cw.Linef("path = bezpath_curve(curve, splinesteps = %d))", sw.SplineSteps)
+		state.bounds = sw.writeRegion(cw, subpaths, state.fillRule, state.tolerance)
+		return nil, nil
 
 	case *ast.CubicBezier:
 		if node.Relative {
-			node.Points = node.Points.Add(state.lastPoint())
+			node.Points = node.Points.Add(state.current)
 		}
-		return node.Points, nil
+		state.current = node.Points.End()
+		state.lastControl = node.Points[1]
+		return state.transform(node.Points), nil
+
+	case *ast.SmoothCubicBezier:
+		start := state.current
+		if node.Relative {
+			node.Points = node.Points.Add(start)
+		}
+		// The first control point is reflected from the previous cubic, or is the current point if there was none
+		control := start
+		switch state.lastCommand.(type) {
+		case *ast.CubicBezier, *ast.SmoothCubicBezier:
+			control = state.lastControl.Reflect(start)
+		}
+		state.current = node.Points.End()
+		state.lastControl = node.Points[0]
+		return state.transform(ast.Coords{control, node.Points[0], node.Points[1]}), nil
 
 	case *ast.LineTo:
 		if node.Relative {
-			node.Coord = node.Coord.Add(state.lastPoint())
+			node.Coord = node.Coord.Add(state.current)
 		}
+		state.current = node.Coord
 		// Convert to a curve, it's easier to create the geometry in OpenSCAD as all bezier
-		return ast.Coords{node.Coord, node.Coord, node.Coord}, nil
+		return state.transform(ast.Coords{node.Coord, node.Coord, node.Coord}), nil
+
+	case *ast.QuadraticBezier:
+		start := state.current
+		if node.Relative {
+			node.Points = node.Points.Add(start)
+		}
+		state.current = node.Points.End()
+		state.lastControl = node.Points[0]
+		return state.transform(ast.QuadraticToCubic(start, node.Points[0], node.Points[1])), nil
+
+	case *ast.SmoothQuadraticBezier:
+		start := state.current
+		if node.Relative {
+			node.Coord = node.Coord.Add(start)
+		}
+		// The control point is reflected from the previous quadratic, or is the current point if there was none
+		control := start
+		switch state.lastCommand.(type) {
+		case *ast.QuadraticBezier, *ast.SmoothQuadraticBezier:
+			control = state.lastControl.Reflect(start)
+		}
+		state.current = node.Coord
+		state.lastControl = control
+		return state.transform(ast.QuadraticToCubic(start, control, node.Coord)), nil
+
+	case *ast.Arc:
+		start := state.current
+		if node.Relative {
+			node.Coord = node.Coord.Add(start)
+		}
+		segments := node.Cubics(start)
+		if len(segments) == 0 {
+			return nil, nil
+		}
+		state.current = node.Coord
+		return std.Map(segments, state.transform), nil
+
+	case *ast.HorizontalLineTo:
+		end := node.End(state.current)
+		state.current = end
+		return state.transform(ast.Coords{end, end, end}), nil
+
+	case *ast.VerticalLineTo:
+		end := node.End(state.current)
+		state.current = end
+		return state.transform(ast.Coords{end, end, end}), nil
 
 	case *ast.ClosePath:
-		c := state.firstPoint()
-		return ast.Coords{c, c, c}, nil
+		c := state.subpathStart
+		if state.current == c {
+			return nil, nil
+		}
+		state.current = c
+		return state.transform(ast.Coords{c, c, c}), nil
 
 	case *ast.Path:
-		node.Name = state.pathID
-		cw.Linef("function %s(%s) =", node.Name, ast.Cursor)
+		node.Name = state.name
+		cw.Linef("function %s() =", node.Name)
 		cw.Indent()
 		defer cw.Unindent()
 		defer func() { state.paths = append(state.paths, node.Name) }()
@@ -221,6 +648,129 @@ func (sw *SCADWriter) walk(cw *ast.CodeWriter, node any, state *walkState) (val
 	default:
 		return nil, fmt.Errorf("unsupported command: %q", reflect.TypeOf(node))
 	}
-	cw.Tab().Lines("path;")
-	return nil, nil
+}
+
+// walkSubpaths walks a path's commands and returns its subpaths. Each subpath is its own curve. A new one starts
+// at each moveto, and after a closepath.
+func (sw *SCADWriter) walkSubpaths(cw *ast.CodeWriter, commands ast.CommandList, state *walkState) ([]subpath, error) {
+	subpaths := []subpath{}
+	var current *subpath
+	for _, child := range commands {
+		if _, isMove := child.(*ast.MoveTo); current == nil && !isMove {
+			subpaths = append(subpaths, subpath{start: state.transform(ast.Coords{state.current})[0]})
+			current = &subpaths[len(subpaths)-1]
+		}
+		r, err := sw.walk(cw, child, state)
+		if err != nil {
+			return nil, fmt.Errorf("failed building curve: %w", err)
+		}
+		state.lastCommand = child
+		switch r := r.(type) {
+		case nil:
+		case ast.Coords:
+			current.segments = append(current.segments, r)
+		case []ast.Coords:
+			current.segments = append(current.segments, r...)
+		default:
+			return nil, fmt.Errorf("type %v is not supported", reflect.TypeOf(r))
+		}
+		switch child.(type) {
+		case *ast.MoveTo, *ast.ClosePath:
+			current = nil
+		}
+	}
+	return subpaths, nil
+}
+
+// subpath is a run of cubic bezier segments, each holding two control points and an end point, in output space.
+// Straight lines are segments whose control points are all the same.
+type subpath struct {
+	start    ast.Coord
+	segments []ast.Coords
+}
+
+// bounds returns the tight bounding box of the subpath's curves.
+func (sp subpath) bounds() ast.Bounds {
+	b := ast.EmptyBounds().Add(sp.start)
+	start := sp.start
+	for _, seg := range sp.segments {
+		b = b.Union(ast.CubicBounds(start, seg))
+		start = seg.End()
+	}
+	return b
+}
+
+// pointsPerLine is how many points of a flattened subpath are written on each line of the output.
+const pointsPerLine = 6
+
+// writeRegion writes the expression for a path's subpaths as a list of polygons. The curves are flattened here,
+// to the given tolerance, rather than in OpenSCAD, and the fill rule decides which subpaths are holes. BOSL2
+// applies the fill rule with make_region. In pure mode, the subpaths are replaced by the boundaries of the area
+// the fill rule fills, so that they can be drawn with the even-odd rule that polygon() uses. It returns the exact
+// bounds of the curves, which are empty if they enclose no area.
+func (sw *SCADWriter) writeRegion(cw *ast.CodeWriter, subpaths []subpath, fillRule svg.FillRule, tolerance float64) ast.Bounds {
+	polygons := []ast.Coords{}
+	bounds := ast.EmptyBounds()
+	for _, sp := range subpaths {
+		if points := sw.flatten(sp, tolerance); len(points) >= 3 {
+			polygons = append(polygons, points)
+			bounds = bounds.Union(sp.bounds())
+		}
+		// Anything shorter is a lone moveto or a single line, which encloses nothing
+	}
+	if sw.Pure {
+		polygons = ast.EvenOddBoundaries(polygons, fillRule == svg.NonZero)
+	}
+	if len(polygons) == 0 {
+		bounds = ast.EmptyBounds()
+	}
+
+	cw.Lines("let(subpaths = [")
+	cw.Indent()
+	for _, points := range polygons {
+		colWidths := [2]int{}
+		for _, w := range points.ColumnWidths(sw.Precision) {
+			colWidths = [2]int{max(colWidths[0], w[0]), max(colWidths[1], w[1])}
+		}
+		cw.Lines("[")
+		cw.Indent()
+		for i := 0; i < len(points); i += pointsPerLine {
+			line := points[i:min(i+pointsPerLine, len(points))]
+			row := make([]string, len(line))
+			for j, coord := range line {
+				row[j] = coord.Columnized(colWidths, sw.Precision)
+			}
+			cw.Lines(strings.Join(row, ", ") + ",")
+		}
+		cw.Unindent()
+		cw.Lines("],")
+	}
+	cw.Unindent()
+	cw.Lines("])")
+	if sw.Pure {
+		cw.Lines("subpaths;")
+	} else {
+		cw.Linef("make_region(subpaths, nonzero = %t);", fillRule == svg.NonZero)
+	}
+	return bounds
+}
+
+// flatten approximates a subpath by a polygon within the tolerance. Points that would be written the same at the
+// output precision are merged, and the end point is dropped if it closes the polygon, since that's implicit.
+func (sw *SCADWriter) flatten(sp subpath, tolerance float64) ast.Coords {
+	points := ast.Coords{sp.start}
+	add := func(p ast.Coord) {
+		if p.Format(sw.Precision) != points.End().Format(sw.Precision) {
+			points = append(points, p)
+		}
+	}
+	for _, seg := range sp.segments {
+		for _, p := range ast.FlattenCubic(points.End(), seg, tolerance) {
+			add(p)
+		}
+	}
+	if len(points) > 1 && points.End().Format(sw.Precision) == points[0].Format(sw.Precision) {
+		points = points[:len(points)-1]
+	}
+	return points
 }
@@ -0,0 +1,242 @@
+package scad
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/mattolenik/svg2scad/log"
+	"github.com/mattolenik/svg2scad/svg"
+	"github.com/mattolenik/svg2scad/svg/ast"
+)
+
+// definition is an element that <use> elements refer to, or a symbol. Its paths are written once as functions,
+// in base space: the coordinate system of the element's parent, scaled to millimetres like the rest of the
+// drawing but not moved. Each <use> then draws those functions' regions with a transform, rather than repeating
+// their points.
+type definition struct {
+	*svg.Definition
+	functions map[*svg.Path]string
+	name      string  // of the symbol's module
+	scale     float64 // the most it is enlarged by where it is drawn, see measureScales
+}
+
+// findDefinitions collects the symbols, then the elements that <use> elements refer to, in document order.
+func (doc *document) findDefinitions() {
+	add := func(d *svg.Definition) {
+		if _, exists := doc.defined[d]; !exists {
+			def := &definition{Definition: d, functions: map[*svg.Path]string{}}
+			doc.defined[d] = def
+			doc.definitions = append(doc.definitions, def)
+		}
+	}
+	for _, symbol := range doc.svg.AllSymbols() {
+		add(symbol)
+	}
+	for _, use := range doc.svg.AllUses() {
+		if id, err := use.Ref(); err == nil {
+			if d, ok := doc.svg.Lookup(id); ok {
+				add(d)
+			}
+		}
+	}
+}
+
+// writeDefinitions writes the functions for the paths of every definition, in base space. Their curves are
+// flattened finely enough to be within the tolerance at the largest scale they are drawn at.
+func (sw *SCADWriter) writeDefinitions(cw *ast.CodeWriter, doc *document) error {
+	for _, def := range doc.definitions {
+		tolerance := sw.Tolerance
+		if def.scale > 0 {
+			tolerance /= def.scale
+		}
+		paths, _ := def.Contents(doc.hidden)
+		unnamed := map[string]int{}
+		for _, path := range paths {
+			name, fallback := path.Name(), path.XMLName.Local
+			if &path.Element != def.Element {
+				// Paths in a symbol or group are named after both, e.g. star_outline
+				unnamed[path.XMLName.Local]++
+				name = def.Name() + "_" + path.Name()
+				if !usable(path.Name()) {
+					name, fallback = "", fmt.Sprintf("%s_%s_%d", def.Name(), path.XMLName.Local, unnamed[path.XMLName.Local])
+				}
+			}
+			local, err := def.Local(&path.Element)
+			if err != nil {
+				log.Warnf("skipping %s %q: %v", path.XMLName.Local, path.ID, err)
+				continue
+			}
+			function := doc.names.Name(name, fallback)
+			state, err := sw.convertPath(cw, path, function, doc.base.Multiply(local), tolerance)
+			if err != nil {
+				return err
+			}
+			if len(state.paths) > 0 {
+				def.functions[path] = function
+			}
+		}
+	}
+	return nil
+}
+
+// symbolShapes returns a shape for each symbol, which draws it in base space.
+func (sw *SCADWriter) symbolShapes(doc *document) []shape {
+	shapes := []shape{}
+	for i, def := range doc.definitions {
+		if !def.IsSymbol() {
+			continue
+		}
+		regions, bounds := sw.place(doc, def, doc.base, []*definition{def})
+		if len(regions) > 0 {
+			def.name = doc.names.Name(def.Name(), fmt.Sprintf("symbol_%d", i+1))
+			shapes = append(shapes, shape{name: def.name, bounds: bounds, regions: regions})
+		}
+	}
+	return shapes
+}
+
+// useShape returns the shape for a <use> in the drawing, or false if it draws nothing.
+func (sw *SCADWriter) useShape(doc *document, use *svg.Use) (shape, bool) {
+	regions, bounds := sw.placeUse(doc, use, doc.viewport.Multiply(use.CTM), nil)
+	if len(regions) == 0 {
+		return shape{}, false
+	}
+	doc.unnamed["use"]++
+	name := doc.names.Name(use.Name(), fmt.Sprintf("use_%d", doc.unnamed["use"]))
+	return shape{name: name, bounds: bounds, regions: regions}, true
+}
+
+// target returns the definition a <use> draws, and the transform it places it with. It fails if the element the
+// use refers to is missing, or the use is inside it. stack holds the definitions being drawn, to detect cycles.
+func (doc *document) target(use *svg.Use, stack []*definition) (*definition, svg.Matrix, error) {
+	id, err := use.Ref()
+	if err != nil {
+		return nil, svg.Identity, err
+	}
+	d, ok := doc.svg.Lookup(id)
+	if !ok {
+		return nil, svg.Identity, fmt.Errorf("use %q, there is no element with the ID %q", use.ID, id)
+	}
+	def := doc.defined[d]
+	if slices.Contains(stack, def) {
+		return nil, svg.Identity, fmt.Errorf("a use of %q inside %q itself", id, id)
+	}
+	placement, err := use.Placement(d)
+	if err != nil {
+		return nil, svg.Identity, fmt.Errorf("use %q: %w", use.ID, err)
+	}
+	return def, placement, nil
+}
+
+// placeUse returns the regions a <use> draws, and their bounds, where m maps the use's coordinate system to
+// output space. Uses that can't be drawn, see target, are skipped with a warning.
+func (sw *SCADWriter) placeUse(doc *document, use *svg.Use, m svg.Matrix, stack []*definition) ([]string, ast.Bounds) {
+	def, placement, err := doc.target(use, stack)
+	if err != nil {
+		log.Warnf("skipping %v", err)
+		return nil, ast.EmptyBounds()
+	}
+	return sw.place(doc, def, m.Multiply(placement), append(stack, def))
+}
+
+// measureScales sets the scale of each definition to the most that a symbol module or any use in the drawing
+// enlarges it by, relative to base space.
+func (doc *document) measureScales() {
+	for _, def := range doc.definitions {
+		if def.IsSymbol() {
+			doc.measure(def, doc.base, []*definition{def})
+		}
+	}
+	for _, use := range doc.uses {
+		if def, placement, err := doc.target(use, nil); err == nil {
+			doc.measure(def, doc.viewport.Multiply(use.CTM).Multiply(placement), []*definition{def})
+		}
+	}
+}
+
+// measure updates the scales of a definition and those it uses, where m maps the coordinate system of the
+// definition's parent to output space, like in place.
+func (doc *document) measure(def *definition, m svg.Matrix, stack []*definition) {
+	def.scale = max(def.scale, doc.baseToOutput(m).MaxScale())
+	_, uses := def.Contents(doc.hidden)
+	for _, use := range uses {
+		local, err := def.Local(&use.Element)
+		if err != nil {
+			continue
+		}
+		if used, placement, err := doc.target(use, stack); err == nil {
+			doc.measure(used, m.Multiply(local).Multiply(placement), append(stack, used))
+		}
+	}
+}
+
+// baseToOutput returns the transform from base space to output space, given m, which maps the coordinate system
+// of a definition's parent to output space.
+func (doc *document) baseToOutput(m svg.Matrix) svg.Matrix {
+	if inverse, err := doc.base.Invert(); err == nil {
+		return m.Multiply(inverse)
+	}
+	return m
+}
+
+// place returns the regions a definition draws, and their bounds, where m maps the coordinate system of the
+// definition's parent to output space.
+func (sw *SCADWriter) place(doc *document, def *definition, m svg.Matrix, stack []*definition) ([]string, ast.Bounds) {
+	regions, bounds := []string{}, ast.EmptyBounds()
+	baseToOutput := doc.baseToOutput(m)
+	paths, uses := def.Contents(doc.hidden)
+	for _, path := range paths {
+		function, ok := def.functions[path]
+		if !ok {
+			continue // it couldn't be converted, see writeDefinitions
+		}
+		local, err := def.Local(&path.Element)
+		if err != nil {
+			continue
+		}
+		// The bounds are found by converting the path again with the whole transform, rather than transforming
+		// those of the function, which would only be exact for some transforms
+		state, err := sw.convertPath(ast.NewCodeWriter(), path, function, m.Multiply(local), sw.Tolerance)
+		if err != nil {
+			continue // the path was already converted once, so this can't happen
+		}
+		bounds = bounds.Union(state.bounds)
+		if isNearIdentity(baseToOutput) {
+			regions = append(regions, function+"()")
+		} else {
+			regions = append(regions, fmt.Sprintf("%s(%s, %s())", APPLY, sw.matrix(baseToOutput), function))
+		}
+	}
+	for _, use := range uses {
+		local, err := def.Local(&use.Element)
+		if err != nil {
+			log.Warnf("skipping use %q: %v", use.ID, err)
+			continue
+		}
+		r, b := sw.placeUse(doc, use, m.Multiply(local), stack)
+		regions, bounds = append(regions, r...), bounds.Union(b)
+	}
+	return regions, bounds
+}
+
+func isNearIdentity(m svg.Matrix) bool {
+	for i, v := range m {
+		if math.Abs(v-svg.Identity[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+// matrix formats an affine transform as the top two rows of the 3x3 matrix OpenSCAD uses. The scale and rotation
+// get more decimal places than the translation, since they multiply coordinates.
+func (sw *SCADWriter) matrix(m svg.Matrix) string {
+	p := sw.Precision
+	if p >= 0 {
+		p += 4
+	}
+	f := func(v float64, precision int) string { return ast.FormatNumber(v, precision) }
+	return fmt.Sprintf("[ [ %s, %s, %s ], [ %s, %s, %s ] ]",
+		f(m[0], p), f(m[2], p), f(m[4], sw.Precision), f(m[1], p), f(m[3], p), f(m[5], sw.Precision))
+}
@@ -0,0 +1,93 @@
+package scad
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mattolenik/svg2scad/svg"
+	"github.com/mattolenik/svg2scad/svg/ast"
+)
+
+const useTestSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="120mm" height="60mm" viewBox="0 0 120 60">
+	<defs>
+		<symbol id="square" viewBox="0 0 10 10"><path d="M0,0h10v10h-10z"/></symbol>
+		<rect id="dot" x="-1" y="-1" width="2" height="2"/>
+		<g id="pair"><use href="#dot" x="-4"/><use href="#dot" x="4"/></g>
+		<g id="loop"><use href="#loop"/></g>
+		<g id="ping"><use href="#pong"/></g>
+		<g id="pong"><use href="#dot"/><use href="#ping"/></g>
+	</defs>
+	<use id="plain" href="#dot" x="10" y="20"/>
+	<use id="xlink" xlink:href="#dot" xmlns:xlink="http://www.w3.org/1999/xlink"/>
+	<use id="scaled" href="#square" x="20" y="30" width="20" height="20"/>
+	<use id="percent" href="#dot" x="50%" y="50%"/>
+	<use id="nested" href="#pair" x="100" y="50"/>
+	<use id="missing" href="#nothing"/>
+	<use id="empty"/>
+	<use id="external" href="other.svg#dot"/>
+	<use id="self" href="#loop"/>
+	<use id="mutual" href="#ping"/>
+</svg>`
+
+func TestUseShapes(t *testing.T) {
+	s, err := svg.ReadSVG(strings.NewReader(useTestSVG), svg.DefaultDPI)
+	if err != nil {
+		t.Fatalf("ReadSVG failed: %v", err)
+	}
+	sw := &SCADWriter{Tolerance: 0.01, DPI: svg.DefaultDPI, Precision: 4, Origin: "center"}
+	doc, err := sw.newDocument(s)
+	if err != nil {
+		t.Fatalf("newDocument failed: %v", err)
+	}
+	if err := sw.writeDefinitions(ast.NewCodeWriter(), doc); err != nil {
+		t.Fatalf("writeDefinitions failed: %v", err)
+	}
+	uses := map[string]*svg.Use{}
+	for _, use := range doc.uses {
+		uses[use.ID] = use
+	}
+
+	tests := []struct {
+		id       string
+		ok       bool
+		regions  int
+		min, max ast.Coord // bounds, in millimetres with y down since the y axis isn't flipped
+	}{
+		{"plain", true, 1, ast.Coord{9, 19}, ast.Coord{11, 21}},
+		{"xlink", true, 1, ast.Coord{-1, -1}, ast.Coord{1, 1}},
+		{"scaled", true, 1, ast.Coord{20, 30}, ast.Coord{40, 50}},
+		{"percent", true, 1, ast.Coord{59, 29}, ast.Coord{61, 31}},
+		{"nested", true, 2, ast.Coord{95, 49}, ast.Coord{105, 51}},
+		{"missing", false, 0, ast.Coord{}, ast.Coord{}},
+		{"empty", false, 0, ast.Coord{}, ast.Coord{}},
+		{"external", false, 0, ast.Coord{}, ast.Coord{}},
+		{"self", false, 0, ast.Coord{}, ast.Coord{}},
+		// ping draws pong, which draws the dot, and ping again, which is skipped
+		{"mutual", true, 1, ast.Coord{-1, -1}, ast.Coord{1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			use, found := uses[tt.id]
+			if !found {
+				t.Fatalf("no use %q in the document", tt.id)
+			}
+			shape, ok := sw.useShape(doc, use)
+			if ok != tt.ok {
+				t.Fatalf("useShape ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if len(shape.regions) != tt.regions {
+				t.Errorf("got %d regions, want %d: %v", len(shape.regions), tt.regions, shape.regions)
+			}
+			for i := range 2 {
+				if math.Abs(shape.bounds.Min[i]-tt.min[i]) > 1e-9 || math.Abs(shape.bounds.Max[i]-tt.max[i]) > 1e-9 {
+					t.Errorf("bounds are %v to %v, want %v to %v", shape.bounds.Min, shape.bounds.Max, tt.min, tt.max)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package scad
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mattolenik/svg2scad/svg"
+	"github.com/mattolenik/svg2scad/svg/ast"
+)
+
+// line returns the segment for a straight line to p, which is a cubic with every point at p.
+func line(x, y float64) ast.Coords {
+	return ast.Coords{{x, y}, {x, y}, {x, y}}
+}
+
+type walkTest struct {
+	name string
+	d    string
+	want []subpath
+}
+
+// testWalk checks the subpaths that walking each test's path data gives, with no transform.
+func testWalk(t *testing.T, tests []walkTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := ast.Parse(tt.name, []byte(tt.d))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			path := tree.(*ast.Path)
+			if path.Unparsed != "" {
+				t.Fatalf("Parse left %q unparsed", path.Unparsed)
+			}
+			state := newWalkState("test", svg.Identity, svg.NonZero)
+			got, err := (&SCADWriter{}).walkSubpaths(ast.NewCodeWriter(), path.Children.(ast.CommandList), state)
+			if err != nil {
+				t.Fatalf("walkSubpaths failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subpaths of %q are %v, want %v", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkSubpaths(t *testing.T) {
+	testWalk(t, []walkTest{
+		{"single subpath", "M0,0 L10,0 L10,10 z", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{line(10, 0), line(10, 10), line(0, 0)}},
+		}},
+		{"closepath at the start point adds no segment", "M0,0 L10,0 L10,10 L0,0 z", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{line(10, 0), line(10, 10), line(0, 0)}},
+		}},
+		{"relative moveto after closepath is from the subpath's start", "M10,10 L20,10 L20,20 z m5,5 l1,0 l0,1 z",
+			[]subpath{
+				{ast.Coord{10, 10}, []ast.Coords{line(20, 10), line(20, 20), line(10, 10)}},
+				{ast.Coord{15, 15}, []ast.Coords{line(16, 15), line(16, 16), line(15, 15)}},
+			}},
+		{"segment after closepath starts a subpath at the previous start", "M0,0 L10,0 L10,10 z L5,0 L5,5 z",
+			[]subpath{
+				{ast.Coord{0, 0}, []ast.Coords{line(10, 0), line(10, 10), line(0, 0)}},
+				{ast.Coord{0, 0}, []ast.Coords{line(5, 0), line(5, 5), line(0, 0)}},
+			}},
+		{"relative segment after closepath", "M10,10 L20,10 L20,20 z l5,0 l0,5 z", []subpath{
+			{ast.Coord{10, 10}, []ast.Coords{line(20, 10), line(20, 20), line(10, 10)}},
+			{ast.Coord{10, 10}, []ast.Coords{line(15, 10), line(15, 15), line(10, 10)}},
+		}},
+		{"second subpath closes to its own start", "M0,0 L10,0 L10,10 z M20,20 L30,20 L30,30 z", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{line(10, 0), line(10, 10), line(0, 0)}},
+			{ast.Coord{20, 20}, []ast.Coords{line(30, 20), line(30, 30), line(20, 20)}},
+		}},
+		{"second subpath without a closepath", "M0,0 L10,0 L10,10 z M20,20 L30,20 L30,30", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{line(10, 0), line(10, 10), line(0, 0)}},
+			{ast.Coord{20, 20}, []ast.Coords{line(30, 20), line(30, 30)}},
+		}},
+		{"consecutive movetos", "M0,0 M5,5 L10,5 L10,10", []subpath{
+			{ast.Coord{5, 5}, []ast.Coords{line(10, 5), line(10, 10)}},
+		}},
+	})
+}
+
+// quadratic returns the segment for a quadratic bezier from start, raised to a cubic.
+func quadratic(start, control, end ast.Coord) ast.Coords {
+	return ast.QuadraticToCubic(start, control, end)
+}
+
+func TestWalkSmoothQuadratic(t *testing.T) {
+	testWalk(t, []walkTest{
+		{"after a quadratic the control point is reflected", "M0,0 Q10,10 20,0 T40,0", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{
+				quadratic(ast.Coord{0, 0}, ast.Coord{10, 10}, ast.Coord{20, 0}),
+				quadratic(ast.Coord{20, 0}, ast.Coord{30, -10}, ast.Coord{40, 0}),
+			}},
+		}},
+		{"after a smooth quadratic its reflected control point is reflected again", "M0,0 Q10,10 20,0 T40,0 T60,0",
+			[]subpath{
+				{ast.Coord{0, 0}, []ast.Coords{
+					quadratic(ast.Coord{0, 0}, ast.Coord{10, 10}, ast.Coord{20, 0}),
+					quadratic(ast.Coord{20, 0}, ast.Coord{30, -10}, ast.Coord{40, 0}),
+					quadratic(ast.Coord{40, 0}, ast.Coord{50, 10}, ast.Coord{60, 0}),
+				}},
+			}},
+		{"relative", "M0,0 q10,10 20,0 t20,0 t20,0", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{
+				quadratic(ast.Coord{0, 0}, ast.Coord{10, 10}, ast.Coord{20, 0}),
+				quadratic(ast.Coord{20, 0}, ast.Coord{30, -10}, ast.Coord{40, 0}),
+				quadratic(ast.Coord{40, 0}, ast.Coord{50, 10}, ast.Coord{60, 0}),
+			}},
+		}},
+		{"after a line the control point is the current point", "M0,0 L10,0 T20,10", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{
+				line(10, 0),
+				quadratic(ast.Coord{10, 0}, ast.Coord{10, 0}, ast.Coord{20, 10}),
+			}},
+		}},
+		{"after a cubic the control point is the current point", "M0,0 C0,10 10,10 10,0 T20,10", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{
+				{{0, 10}, {10, 10}, {10, 0}},
+				quadratic(ast.Coord{10, 0}, ast.Coord{10, 0}, ast.Coord{20, 10}),
+			}},
+		}},
+		{"at the start the control point is the current point", "M5,5 T20,10", []subpath{
+			{ast.Coord{5, 5}, []ast.Coords{quadratic(ast.Coord{5, 5}, ast.Coord{5, 5}, ast.Coord{20, 10})}},
+		}},
+	})
+}
+
+func TestWalkSmoothCubic(t *testing.T) {
+	testWalk(t, []walkTest{
+		{"after a cubic the second control point is reflected", "M0,0 C0,10 10,10 10,0 S20,-10 20,0", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{{{0, 10}, {10, 10}, {10, 0}}, {{10, -10}, {20, -10}, {20, 0}}}},
+		}},
+		{"after a smooth cubic its second control point is reflected", "M0,0 C0,10 10,10 10,0 S20,-10 20,0 S30,10 30,0",
+			[]subpath{
+				{ast.Coord{0, 0}, []ast.Coords{
+					{{0, 10}, {10, 10}, {10, 0}}, {{10, -10}, {20, -10}, {20, 0}}, {{20, 10}, {30, 10}, {30, 0}},
+				}},
+			}},
+		{"relative", "M0,0 c0,10 10,10 10,0 s10,-10 10,0 s10,10 10,0", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{
+				{{0, 10}, {10, 10}, {10, 0}}, {{10, -10}, {20, -10}, {20, 0}}, {{20, 10}, {30, 10}, {30, 0}},
+			}},
+		}},
+		{"after a line the first control point is the current point", "M0,0 L10,0 S20,10 20,0", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{line(10, 0), {{10, 0}, {20, 10}, {20, 0}}}},
+		}},
+		{"after a quadratic the first control point is the current point", "M0,0 Q5,5 10,0 S20,10 20,0", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{
+				quadratic(ast.Coord{0, 0}, ast.Coord{5, 5}, ast.Coord{10, 0}),
+				{{10, 0}, {20, 10}, {20, 0}},
+			}},
+		}},
+		{"at the start the first control point is the current point", "M5,5 S20,10 20,0", []subpath{
+			{ast.Coord{5, 5}, []ast.Coords{{{5, 5}, {20, 10}, {20, 0}}}},
+		}},
+	})
+}
+
+func TestWalkHorizontalVertical(t *testing.T) {
+	testWalk(t, []walkTest{
+		{"absolute", "M10,10 H20 V20 H10 V10", []subpath{
+			{ast.Coord{10, 10}, []ast.Coords{line(20, 10), line(20, 20), line(10, 20), line(10, 10)}},
+		}},
+		{"relative", "M10,10 h10 v10 h-10 v-10", []subpath{
+			{ast.Coord{10, 10}, []ast.Coords{line(20, 10), line(20, 20), line(10, 20), line(10, 10)}},
+		}},
+		{"relative with several arguments", "M10,10 h5 5 v5 5", []subpath{
+			{ast.Coord{10, 10}, []ast.Coords{line(15, 10), line(20, 10), line(20, 15), line(20, 20)}},
+		}},
+		{"relative after a curve", "M0,0 C0,10 10,10 10,5 h5 v-5", []subpath{
+			{ast.Coord{0, 0}, []ast.Coords{{{0, 10}, {10, 10}, {10, 5}}, line(15, 5), line(15, 0)}},
+		}},
+		{"relative after closepath", "M10,10 h10 v10 z h5 v5", []subpath{
+			{ast.Coord{10, 10}, []ast.Coords{line(20, 10), line(20, 20), line(10, 10)}},
+			{ast.Coord{10, 10}, []ast.Coords{line(15, 10), line(15, 15)}},
+		}},
+	})
+}
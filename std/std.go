@@ -36,6 +36,16 @@ func MapP[TIn any, TOut any](items []TIn, mapFn func(v *TIn) TOut) []TOut {
 	return result
 }
 
+func Filter[T any](items []T, keep func(v T) bool) []T {
+	result := []T{}
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 func EnsureSuffix(str, suffix string) string {
 	if strings.HasSuffix(str, suffix) {
 		return str
@@ -0,0 +1,80 @@
+package ast
+
+import "math"
+
+// Cubics approximates the arc with cubic bezier segments, starting at the absolute point from. The arc's
+// Coord must already be absolute. Each segment spans at most 90 degrees of the ellipse, and is returned as its
+// two control points and end point. It follows the endpoint to center conversion in the SVG spec, appendix B.2.
+func (a *Arc) Cubics(from Coord) []Coords {
+	x1, y1 := from.XY()
+	x2, y2 := a.Coord.XY()
+	if x1 == x2 && y1 == y2 {
+		// The spec says an arc to the current point is omitted entirely
+		return nil
+	}
+	rx, ry := a.Radii.XY()
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		// Degenerate ellipses are treated as straight lines
+		return []Coords{{from, a.Coord, a.Coord}}
+	}
+	sinPhi, cosPhi := math.Sincos(a.Rotation * math.Pi / 180)
+
+	// Step 1: the start point in a frame centred between the ends and aligned with the ellipse's axes
+	dx, dy := (x1-x2)/2, (y1-y2)/2
+	x1p := cosPhi*dx + sinPhi*dy
+	y1p := -sinPhi*dx + cosPhi*dy
+
+	// Scale up radii that are too small to span the ends
+	if lambda := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx, ry = rx*s, ry*s
+	}
+
+	// Step 2: the centre in the same frame
+	num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+	den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+	coef := math.Sqrt(math.Max(0, num/den))
+	if a.LargeArc == a.Sweep {
+		coef = -coef
+	}
+	cxp, cyp := coef*rx*y1p/ry, -coef*ry*x1p/rx
+
+	// Step 3: the centre in user space
+	cx := cosPhi*cxp - sinPhi*cyp + (x1+x2)/2
+	cy := sinPhi*cxp + cosPhi*cyp + (y1+y2)/2
+
+	// Step 4: start angle and sweep
+	theta1 := math.Atan2((y1p-cyp)/ry, (x1p-cxp)/rx)
+	theta2 := math.Atan2((-y1p-cyp)/ry, (-x1p-cxp)/rx)
+	delta := theta2 - theta1
+	if a.Sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !a.Sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+
+	// Maps a point on the unit circle to the ellipse
+	point := func(ux, uy float64) (float64, float64) {
+		return cx + rx*cosPhi*ux - ry*sinPhi*uy, cy + rx*sinPhi*ux + ry*cosPhi*uy
+	}
+
+	n := max(1, int(math.Ceil(math.Abs(delta)/(math.Pi/2)-1e-9)))
+	step := delta / float64(n)
+	k := 4.0 / 3.0 * math.Tan(step/4)
+	result := make([]Coords, n)
+	for i := 0; i < n; i++ {
+		t1 := theta1 + float64(i)*step
+		t2 := t1 + step
+		sin1, cos1 := math.Sincos(t1)
+		sin2, cos2 := math.Sincos(t2)
+		result[i] = Coords{
+			NewCoord(point(cos1-k*sin1, sin1+k*cos1)),
+			NewCoord(point(cos2+k*sin2, sin2-k*cos2)),
+			NewCoord(point(cos2, sin2)),
+		}
+	}
+	// Land exactly on the given end point rather than on an approximation of it
+	result[n-1][2] = a.Coord
+	return result
+}
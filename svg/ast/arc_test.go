@@ -0,0 +1,92 @@
+package ast
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestArcCubics(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     Coord
+		arc      Arc
+		segments int
+		centre   Coord
+		radii    Coord // after scaling up radii that are too small
+		through  Coord // a point the arc passes through, which tells the sweep and large arc flags were followed
+	}{
+		{"quarter circle",
+			Coord{10, 0}, Arc{Radii: Coord{10, 10}, Sweep: true, Coord: Coord{0, 10}},
+			1, Coord{0, 0}, Coord{10, 10}, Coord{10 / math.Sqrt2, 10 / math.Sqrt2}},
+		{"half circle with positive sweep",
+			Coord{0, 0}, Arc{Radii: Coord{10, 10}, Sweep: true, Coord: Coord{20, 0}},
+			2, Coord{10, 0}, Coord{10, 10}, Coord{10, -10}},
+		{"half circle with negative sweep",
+			Coord{0, 0}, Arc{Radii: Coord{10, 10}, Coord: Coord{20, 0}},
+			2, Coord{10, 0}, Coord{10, 10}, Coord{10, 10}},
+		{"large arc",
+			Coord{10, 0}, Arc{Radii: Coord{10, 10}, LargeArc: true, Sweep: true, Coord: Coord{0, 10}},
+			3, Coord{10, 10}, Coord{10, 10}, Coord{20, 10}},
+		{"radii too small to reach",
+			Coord{0, 0}, Arc{Radii: Coord{5, 5}, Sweep: true, Coord: Coord{40, 0}},
+			2, Coord{20, 0}, Coord{20, 20}, Coord{20, -20}},
+		{"negative radii",
+			Coord{0, 0}, Arc{Radii: Coord{-10, -10}, Sweep: true, Coord: Coord{20, 0}},
+			2, Coord{10, 0}, Coord{10, 10}, Coord{10, -10}},
+		{"rotated ellipse",
+			Coord{0, 0}, Arc{Radii: Coord{20, 10}, Rotation: 90, Sweep: true, Coord: Coord{0, 40}},
+			2, Coord{0, 20}, Coord{20, 10}, Coord{10, 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cubics := tt.arc.Cubics(tt.from)
+			if len(cubics) != tt.segments {
+				t.Fatalf("got %d segments, want %d", len(cubics), tt.segments)
+			}
+			if end := cubics[len(cubics)-1][2]; end != tt.arc.Coord {
+				t.Errorf("ends at %v, want exactly %v", end, tt.arc.Coord)
+			}
+			sinPhi, cosPhi := math.Sincos(tt.arc.Rotation * math.Pi / 180)
+			nearest := math.Inf(1)
+			start := tt.from
+			for _, c := range cubics {
+				for t0 := 0.0; t0 <= 1; t0 += 1.0 / 32 {
+					p := cubicPoint(start, c[0], c[1], c[2], t0)
+					// The point in the ellipse's own frame, where it should be on the unit circle
+					dx, dy := p[0]-tt.centre[0], p[1]-tt.centre[1]
+					ux := (cosPhi*dx + sinPhi*dy) / tt.radii[0]
+					uy := (-sinPhi*dx + cosPhi*dy) / tt.radii[1]
+					if r := math.Hypot(ux, uy); math.Abs(r-1) > 1e-3 {
+						t.Fatalf("point %v is off the ellipse by %v of its radius", p, r-1)
+					}
+					nearest = min(nearest, math.Hypot(p[0]-tt.through[0], p[1]-tt.through[1]))
+				}
+				start = c[2]
+			}
+			if nearest > 0.01*tt.radii[0] {
+				t.Errorf("the arc doesn't pass through %v, it gets within %v of it", tt.through, nearest)
+			}
+		})
+	}
+}
+
+func TestArcCubicsDegenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		from Coord
+		arc  Arc
+		want []Coords
+	}{
+		{"end at the start", Coord{5, 5}, Arc{Radii: Coord{10, 10}, Coord: Coord{5, 5}}, nil},
+		{"zero radius", Coord{0, 0}, Arc{Radii: Coord{0, 10}, Coord: Coord{10, 0}},
+			[]Coords{{{0, 0}, {10, 0}, {10, 0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.arc.Cubics(tt.from); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
+	"strconv"
 	"strings"
 )
 
@@ -13,32 +15,58 @@ type Curve struct {
 
 type CommandList []any
 
-type Coord [2]string
+type Coord [2]float64
 
-func (c Coord) String() string {
-	if c[0] == "_" {
-		return "cursor"
+func NewCoord(x, y float64) Coord {
+	return Coord{x, y}
+}
+
+func (c Coord) XY() (float64, float64) {
+	return c[0], c[1]
+}
+
+// Map applies fn to the coordinate.
+func (c Coord) Map(fn func(x, y float64) (float64, float64)) Coord {
+	return NewCoord(fn(c.XY()))
+}
+
+// FormatNumber formats v with at most precision decimal places and no trailing zeros. A negative precision
+// gives the shortest representation that round-trips.
+func FormatNumber(v float64, precision int) string {
+	if precision >= 0 {
+		scale := math.Pow(10, float64(precision))
+		v = math.Round(v*scale) / scale
+	}
+	if v == 0 {
+		v = 0 // no negative zero
 	}
-	return fmt.Sprintf("[ %s, %s ]", c[0], c[1])
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Format returns the coordinate's components formatted with FormatNumber.
+func (c Coord) Format(precision int) [2]string {
+	return [2]string{FormatNumber(c[0], precision), FormatNumber(c[1], precision)}
+}
+
+func (c Coord) String() string {
+	f := c.Format(-1)
+	return fmt.Sprintf("[ %s, %s ]", f[0], f[1])
 }
 
 func (c Coord) Add(coord Coord) Coord {
 	return Coord{c[0] + coord[0], c[1] + coord[1]}
 }
 
-func (c Coord) ColumnWidths() [2]int {
-	return [2]int{len(c[0]), len(c[1])}
+func (c Coord) ColumnWidths(precision int) [2]int {
+	f := c.Format(precision)
+	return [2]int{len(f[0]), len(f[1])}
 }
 
-func (c Coord) Columnized(colWidths [2]int) string {
-	if len(c) != len(colWidths) {
-		panic(fmt.Errorf("cannot format coord of length %d using a width set of length %d", len(c), len(colWidths)))
-	}
-	return fmt.Sprintf("[ %*s, %*s ]", colWidths[0], c[0], colWidths[1], c[1])
+func (c Coord) Columnized(colWidths [2]int, precision int) string {
+	f := c.Format(precision)
+	return fmt.Sprintf("[ %*s, %*s ]", colWidths[0], f[0], colWidths[1], f[1])
 }
 
-var Cursor = Coord{"_", "_"} // Represents "cursor" that isn't a numeric value
-
 type Coords []Coord
 
 func (c Coords) End() Coord {
@@ -64,26 +92,35 @@ func (c Coords) GoString() string {
 func (c Coords) Add(coord Coord) Coords {
 	result := make(Coords, len(c))
 	for i, cc := range c {
-		result[i] = Coord{fmt.Sprintf("%v + %v", cc[0], coord[0]), fmt.Sprintf("%v + %v", cc[1], coord[1])}
+		result[i] = cc.Add(coord)
+	}
+	return result
+}
+
+// Map applies fn to the numeric value of every coordinate.
+func (c Coords) Map(fn func(x, y float64) (float64, float64)) Coords {
+	result := make(Coords, len(c))
+	for i, cc := range c {
+		result[i] = cc.Map(fn)
 	}
 	return result
 }
 
-func (c Coords) ColumnWidths() [][2]int {
+func (c Coords) ColumnWidths(precision int) [][2]int {
 	result := make([][2]int, len(c))
 	for i, coord := range c {
-		result[i] = coord.ColumnWidths()
+		result[i] = coord.ColumnWidths(precision)
 	}
 	return result
 }
 
-func (c Coords) Columnized(colWidths [][2]int) string {
+func (c Coords) Columnized(colWidths [][2]int, precision int) string {
 	if len(c) != len(colWidths) {
 		panic(fmt.Errorf("cannot format coord set of length %d using a width set of length %d", len(c), len(colWidths)))
 	}
 	formattedCoords := make([]string, len(c))
 	for i, coord := range c {
-		formattedCoords[i] = coord.Columnized(colWidths[i])
+		formattedCoords[i] = coord.Columnized(colWidths[i], precision)
 	}
 	return "[ " + strings.Join(formattedCoords, ", ") + " ]"
 }
@@ -98,17 +135,84 @@ type LineTo struct {
 	Relative bool
 }
 
+// HorizontalLineTo is a line to X that keeps the current point's y coordinate.
+type HorizontalLineTo struct {
+	X        float64
+	Relative bool
+}
+
+// End returns the absolute end point of the line, given the current point.
+func (h *HorizontalLineTo) End(current Coord) Coord {
+	if h.Relative {
+		return current.Add(Coord{h.X, 0})
+	}
+	return Coord{h.X, current[1]}
+}
+
+// VerticalLineTo is a line to Y that keeps the current point's x coordinate.
+type VerticalLineTo struct {
+	Y        float64
+	Relative bool
+}
+
+// End returns the absolute end point of the line, given the current point.
+func (v *VerticalLineTo) End(current Coord) Coord {
+	if v.Relative {
+		return current.Add(Coord{0, v.Y})
+	}
+	return Coord{current[0], v.Y}
+}
+
 type CubicBezier struct {
 	Points   Coords
 	Relative bool
 }
 
+// SmoothCubicBezier holds the second control point and the end point of a cubic bezier. Its first control point
+// is the reflection of the previous cubic's second one.
+type SmoothCubicBezier struct {
+	Points   Coords
+	Relative bool
+}
+
+// QuadraticBezier holds a control point and an end point.
 type QuadraticBezier struct {
-	Points Coords
+	Points   Coords
+	Relative bool
+}
+
+// SmoothQuadraticBezier is a quadratic bezier whose control point is the reflection of the previous one.
+type SmoothQuadraticBezier struct {
+	Coord    Coord
+	Relative bool
+}
+
+// Reflect returns the reflection of c about the point center.
+func (c Coord) Reflect(center Coord) Coord {
+	return Coord{2*center[0] - c[0], 2*center[1] - c[1]}
+}
+
+// QuadraticToCubic raises the quadratic bezier from start, with the given control and end points, to the
+// equivalent cubic. The result holds the cubic's two control points and end point.
+func QuadraticToCubic(start, control, end Coord) Coords {
+	qx, qy := control.XY()
+	twoThirds := func(x, y float64) (float64, float64) { return x + 2.0/3.0*(qx-x), y + 2.0/3.0*(qy-y) }
+	return Coords{start.Map(twoThirds), end.Map(twoThirds), end}
+}
+
+// Arc is an elliptical arc from the current point to Coord.
+type Arc struct {
+	Radii    Coord
+	Rotation float64 // of the ellipse's x axis, in degrees
+	LargeArc bool
+	Sweep    bool
+	Coord    Coord
+	Relative bool
 }
 
 type ClosePath struct{}
 
+// Color is an sRGB color, with each channel from 0 to 255. A is the alpha channel, where 0 is transparent.
 type Color struct {
 	R int
 	G int
@@ -116,9 +220,15 @@ type Color struct {
 	A int
 }
 
+// String returns the color in #rrggbbaa notation.
+func (c Color) String() string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
 type Path struct {
 	Name     string
 	Children any
+	Unparsed string // the path data from the first error on, which isn't drawn
 }
 
 type CodeWriter struct {
@@ -161,6 +271,12 @@ func (cw *CodeWriter) Lines(code ...string) *CodeWriter {
 	return cw
 }
 
+// Append adds the code written to other, with the indentation it was written at.
+func (cw *CodeWriter) Append(other *CodeWriter) *CodeWriter {
+	cw.buf.Write(other.buf.Bytes())
+	return cw
+}
+
 func (cw *CodeWriter) BlankLine() *CodeWriter {
 	cw.BlankLines(1)
 	return cw
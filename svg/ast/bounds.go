@@ -0,0 +1,90 @@
+package ast
+
+import "math"
+
+// Bounds is an axis-aligned bounding box.
+type Bounds struct {
+	Min Coord
+	Max Coord
+}
+
+// EmptyBounds returns bounds that contain nothing, which grow to fit whatever is added to them.
+func EmptyBounds() Bounds {
+	return Bounds{
+		Min: Coord{math.Inf(1), math.Inf(1)},
+		Max: Coord{math.Inf(-1), math.Inf(-1)},
+	}
+}
+
+func (b Bounds) IsEmpty() bool {
+	return b.Min[0] > b.Max[0] || b.Min[1] > b.Max[1]
+}
+
+// Add returns the bounds grown to contain p.
+func (b Bounds) Add(p Coord) Bounds {
+	return Bounds{
+		Min: Coord{min(b.Min[0], p[0]), min(b.Min[1], p[1])},
+		Max: Coord{max(b.Max[0], p[0]), max(b.Max[1], p[1])},
+	}
+}
+
+// Union returns the bounds grown to contain o.
+func (b Bounds) Union(o Bounds) Bounds {
+	if o.IsEmpty() {
+		return b
+	}
+	return b.Add(o.Min).Add(o.Max)
+}
+
+func (b Bounds) Size() Coord {
+	if b.IsEmpty() {
+		return Coord{0, 0}
+	}
+	return Coord{b.Max[0] - b.Min[0], b.Max[1] - b.Min[1]}
+}
+
+// CubicBounds returns the tight bounding box of the cubic bezier from start, with the given two control points
+// and end point. Besides the end points, it contains the curve's extrema, where its derivative is zero, rather
+// than the control points, which usually lie outside the curve.
+func CubicBounds(start Coord, segment Coords) Bounds {
+	p0, p1, p2, p3 := start, segment[0], segment[1], segment[2]
+	b := EmptyBounds().Add(p0).Add(p3)
+	for axis := 0; axis < 2; axis++ {
+		// The derivative, divided by 3, is a*t^2 + b*t + c
+		qa := -p0[axis] + 3*p1[axis] - 3*p2[axis] + p3[axis]
+		qb := 2 * (p0[axis] - 2*p1[axis] + p2[axis])
+		qc := p1[axis] - p0[axis]
+		for _, t := range quadraticRoots(qa, qb, qc) {
+			if t > 0 && t < 1 {
+				b = b.Add(cubicPoint(p0, p1, p2, p3, t))
+			}
+		}
+	}
+	return b
+}
+
+// quadraticRoots returns the real roots of a*t^2 + b*t + c, including when a is zero and it's linear.
+func quadraticRoots(a, b, c float64) []float64 {
+	const epsilon = 1e-12
+	if math.Abs(a) < epsilon {
+		if math.Abs(b) < epsilon {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return nil
+	}
+	sqrtDisc := math.Sqrt(disc)
+	return []float64{(-b + sqrtDisc) / (2 * a), (-b - sqrtDisc) / (2 * a)}
+}
+
+func cubicPoint(p0, p1, p2, p3 Coord, t float64) Coord {
+	mt := 1 - t
+	a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+	return Coord{
+		a*p0[0] + b*p1[0] + c*p2[0] + d*p3[0],
+		a*p0[1] + b*p1[1] + c*p2[1] + d*p3[1],
+	}
+}
@@ -0,0 +1,84 @@
+package ast
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestCubicBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    Coord
+		segment  Coords
+		min, max Coord
+	}{
+		{"monotonic", Coord{0, 0}, Coords{{10, 5}, {20, 15}, {30, 20}}, Coord{0, 0}, Coord{30, 20}},
+		// The control points are at y = 40, but the curve only reaches 30 halfway along
+		{"extremum inside with control points outside", Coord{0, 0}, Coords{{0, 40}, {40, 40}, {40, 0}},
+			Coord{0, 0}, Coord{40, 30}},
+		{"extrema on both axes", Coord{0, 0}, Coords{{-20, 40}, {60, 40}, {40, 0}},
+			Coord{-3.2379, 0}, Coord{43.2379, 30}},
+		// -p0 + 3p1 - 3p2 + p3 is 0 on the x axis, so the derivative is linear there, with its root at t = 0.5
+		{"linear derivative", Coord{0, 0}, Coords{{20, 0}, {20, 10}, {0, 10}}, Coord{0, 0}, Coord{15, 10}},
+		{"straight line", Coord{0, 0}, Coords{{10, 10}, {20, 20}, {30, 30}}, Coord{0, 0}, Coord{30, 30}},
+		{"straight line doubling back", Coord{0, 0}, Coords{{-10, 0}, {40, 0}, {30, 0}},
+			Coord{-1.3299, 0}, Coord{31.3299, 0}},
+		{"single point", Coord{5, 5}, Coords{{5, 5}, {5, 5}, {5, 5}}, Coord{5, 5}, Coord{5, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := CubicBounds(tt.start, tt.segment)
+			for i := range 2 {
+				if math.Abs(b.Min[i]-tt.min[i]) > 1e-4 || math.Abs(b.Max[i]-tt.max[i]) > 1e-4 {
+					t.Fatalf("bounds are %v to %v, want %v to %v", b.Min, b.Max, tt.min, tt.max)
+				}
+			}
+			// The bounds must be tight: every sampled point is inside them, and they reach no further than the
+			// sampled points do
+			sampled := EmptyBounds()
+			for i := 0; i <= 10000; i++ {
+				p := cubicPoint(tt.start, tt.segment[0], tt.segment[1], tt.segment[2], float64(i)/10000)
+				sampled = sampled.Add(p)
+			}
+			for i := range 2 {
+				if sampled.Min[i] < b.Min[i]-1e-9 || sampled.Max[i] > b.Max[i]+1e-9 {
+					t.Errorf("sampled points reach %v to %v, outside the bounds %v to %v", sampled.Min, sampled.Max, b.Min, b.Max)
+				}
+				if b.Min[i] < sampled.Min[i]-1e-3 || b.Max[i] > sampled.Max[i]+1e-3 {
+					t.Errorf("bounds %v to %v are looser than the sampled points %v to %v", b.Min, b.Max, sampled.Min, sampled.Max)
+				}
+			}
+		})
+	}
+}
+
+func TestQuadraticRoots(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b, c float64
+		want    []float64
+	}{
+		{"two roots", 1, -3, 2, []float64{1, 2}},
+		{"double root", 1, -2, 1, []float64{1, 1}},
+		{"no real roots", 1, 0, 1, nil},
+		{"linear", 0, 2, -1, []float64{0.5}},
+		{"nearly linear", 1e-15, 2, -1, []float64{0.5}},
+		{"constant", 0, 0, 1, nil},
+		{"zero", 0, 0, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := quadraticRoots(tt.a, tt.b, tt.c)
+			sort.Float64s(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("quadraticRoots(%v, %v, %v) = %v, want %v", tt.a, tt.b, tt.c, got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Fatalf("quadraticRoots(%v, %v, %v) = %v, want %v", tt.a, tt.b, tt.c, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -27,23 +27,38 @@ var g = &grammar{
 	rules: []*rule{
 		{
 			name: "Path",
-			pos:  position{line: 14, col: 1, offset: 207},
+			pos:  position{line: 16, col: 1, offset: 330},
 			expr: &actionExpr{
-				pos: position{line: 14, col: 9, offset: 215},
+				pos: position{line: 16, col: 9, offset: 338},
 				run: (*parser).callonPath1,
 				expr: &seqExpr{
-					pos: position{line: 14, col: 9, offset: 215},
+					pos: position{line: 16, col: 9, offset: 338},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 14, col: 9, offset: 215},
+							pos:  position{line: 16, col: 9, offset: 338},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 14, col: 11, offset: 217},
+							pos:   position{line: 16, col: 11, offset: 340},
 							label: "curve",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 16, col: 17, offset: 346},
+								expr: &ruleRefExpr{
+									pos:  position{line: 16, col: 17, offset: 346},
+									name: "Curve",
+								},
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 16, col: 24, offset: 353},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 16, col: 26, offset: 355},
+							label: "rest",
 							expr: &ruleRefExpr{
-								pos:  position{line: 14, col: 17, offset: 223},
-								name: "Curve",
+								pos:  position{line: 16, col: 31, offset: 360},
+								name: "Rest",
 							},
 						},
 					},
@@ -52,22 +67,22 @@ var g = &grammar{
 		},
 		{
 			name: "Seq",
-			pos:  position{line: 18, col: 1, offset: 273},
+			pos:  position{line: 24, col: 1, offset: 534},
 			expr: &actionExpr{
-				pos: position{line: 18, col: 8, offset: 280},
+				pos: position{line: 24, col: 8, offset: 541},
 				run: (*parser).callonSeq1,
 				expr: &seqExpr{
-					pos: position{line: 18, col: 8, offset: 280},
+					pos: position{line: 24, col: 8, offset: 541},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 18, col: 8, offset: 280},
+							pos:  position{line: 24, col: 8, offset: 541},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 18, col: 10, offset: 282},
+							pos:   position{line: 24, col: 10, offset: 543},
 							label: "seq",
 							expr: &ruleRefExpr{
-								pos:  position{line: 18, col: 15, offset: 287},
+								pos:  position{line: 24, col: 15, offset: 548},
 								name: "Curve",
 							},
 						},
@@ -77,17 +92,17 @@ var g = &grammar{
 		},
 		{
 			name: "Curve",
-			pos:  position{line: 22, col: 1, offset: 319},
+			pos:  position{line: 28, col: 1, offset: 580},
 			expr: &actionExpr{
-				pos: position{line: 22, col: 10, offset: 328},
+				pos: position{line: 28, col: 10, offset: 589},
 				run: (*parser).callonCurve1,
 				expr: &labeledExpr{
-					pos:   position{line: 22, col: 10, offset: 328},
+					pos:   position{line: 28, col: 10, offset: 589},
 					label: "cmds",
 					expr: &oneOrMoreExpr{
-						pos: position{line: 22, col: 15, offset: 333},
+						pos: position{line: 28, col: 15, offset: 594},
 						expr: &ruleRefExpr{
-							pos:  position{line: 22, col: 15, offset: 333},
+							pos:  position{line: 28, col: 15, offset: 594},
 							name: "Command",
 						},
 					},
@@ -96,37 +111,49 @@ var g = &grammar{
 		},
 		{
 			name: "Command",
-			pos:  position{line: 26, col: 1, offset: 402},
+			pos:  position{line: 37, col: 1, offset: 857},
 			expr: &actionExpr{
-				pos: position{line: 26, col: 12, offset: 413},
+				pos: position{line: 37, col: 12, offset: 868},
 				run: (*parser).callonCommand1,
 				expr: &seqExpr{
-					pos: position{line: 26, col: 12, offset: 413},
+					pos: position{line: 37, col: 12, offset: 868},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 26, col: 12, offset: 413},
+							pos:  position{line: 37, col: 12, offset: 868},
 							name: "_",
 						},
 						&labeledExpr{
-							pos:   position{line: 26, col: 14, offset: 415},
+							pos:   position{line: 37, col: 14, offset: 870},
 							label: "val",
 							expr: &choiceExpr{
-								pos: position{line: 26, col: 19, offset: 420},
+								pos: position{line: 37, col: 19, offset: 875},
 								alternatives: []any{
 									&ruleRefExpr{
-										pos:  position{line: 26, col: 19, offset: 420},
-										name: "Move",
+										pos:  position{line: 37, col: 19, offset: 875},
+										name: "MoveTo",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 26, col: 26, offset: 427},
+										pos:  position{line: 37, col: 28, offset: 884},
 										name: "LineTo",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 26, col: 35, offset: 436},
+										pos:  position{line: 37, col: 37, offset: 893},
+										name: "HorizontalLineTo",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 37, col: 56, offset: 912},
+										name: "VerticalLineTo",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 37, col: 73, offset: 929},
 										name: "Bezier",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 26, col: 44, offset: 445},
+										pos:  position{line: 37, col: 82, offset: 938},
+										name: "Arc",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 37, col: 88, offset: 944},
 										name: "ClosePath",
 									},
 								},
@@ -136,54 +163,32 @@ var g = &grammar{
 				},
 			},
 		},
-		{
-			name: "Move",
-			pos:  position{line: 30, col: 1, offset: 481},
-			expr: &choiceExpr{
-				pos: position{line: 30, col: 9, offset: 489},
-				alternatives: []any{
-					&ruleRefExpr{
-						pos:  position{line: 30, col: 9, offset: 489},
-						name: "MoveTo",
-					},
-					&ruleRefExpr{
-						pos:  position{line: 30, col: 18, offset: 498},
-						name: "MoveH",
-					},
-					&ruleRefExpr{
-						pos:  position{line: 30, col: 26, offset: 506},
-						name: "MoveV",
-					},
-				},
-			},
-		},
 		{
 			name: "MoveTo",
-			pos:  position{line: 32, col: 1, offset: 513},
+			pos:  position{line: 41, col: 1, offset: 980},
 			expr: &actionExpr{
-				pos: position{line: 32, col: 11, offset: 523},
+				pos: position{line: 41, col: 11, offset: 990},
 				run: (*parser).callonMoveTo1,
 				expr: &seqExpr{
-					pos: position{line: 32, col: 11, offset: 523},
+					pos: position{line: 41, col: 11, offset: 990},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 32, col: 11, offset: 523},
+							pos:   position{line: 41, col: 11, offset: 990},
 							label: "rel",
 							expr: &ruleRefExpr{
-								pos:  position{line: 32, col: 15, offset: 527},
+								pos:  position{line: 41, col: 15, offset: 994},
 								name: "move",
 							},
 						},
-						&ruleRefExpr{
-							pos:  position{line: 32, col: 20, offset: 532},
-							name: "_",
-						},
 						&labeledExpr{
-							pos:   position{line: 32, col: 22, offset: 534},
-							label: "coord",
-							expr: &ruleRefExpr{
-								pos:  position{line: 32, col: 28, offset: 540},
-								name: "Coord",
+							pos:   position{line: 41, col: 20, offset: 999},
+							label: "coords",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 41, col: 27, offset: 1006},
+								expr: &ruleRefExpr{
+									pos:  position{line: 41, col: 27, offset: 1006},
+									name: "NextCoord",
+								},
 							},
 						},
 					},
@@ -191,32 +196,31 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "MoveH",
-			pos:  position{line: 36, col: 1, offset: 619},
+			name: "LineTo",
+			pos:  position{line: 54, col: 1, offset: 1416},
 			expr: &actionExpr{
-				pos: position{line: 36, col: 10, offset: 628},
-				run: (*parser).callonMoveH1,
+				pos: position{line: 54, col: 11, offset: 1426},
+				run: (*parser).callonLineTo1,
 				expr: &seqExpr{
-					pos: position{line: 36, col: 10, offset: 628},
+					pos: position{line: 54, col: 11, offset: 1426},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 36, col: 10, offset: 628},
+							pos:   position{line: 54, col: 11, offset: 1426},
 							label: "rel",
 							expr: &ruleRefExpr{
-								pos:  position{line: 36, col: 14, offset: 632},
-								name: "moveh",
+								pos:  position{line: 54, col: 15, offset: 1430},
+								name: "lineto",
 							},
 						},
-						&ruleRefExpr{
-							pos:  position{line: 36, col: 20, offset: 638},
-							name: "_",
-						},
 						&labeledExpr{
-							pos:   position{line: 36, col: 22, offset: 640},
-							label: "val",
-							expr: &ruleRefExpr{
-								pos:  position{line: 36, col: 26, offset: 644},
-								name: "Number",
+							pos:   position{line: 54, col: 22, offset: 1437},
+							label: "coords",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 54, col: 29, offset: 1444},
+								expr: &ruleRefExpr{
+									pos:  position{line: 54, col: 29, offset: 1444},
+									name: "NextCoord",
+								},
 							},
 						},
 					},
@@ -224,32 +228,31 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "MoveV",
-			pos:  position{line: 40, col: 1, offset: 735},
+			name: "HorizontalLineTo",
+			pos:  position{line: 60, col: 1, offset: 1605},
 			expr: &actionExpr{
-				pos: position{line: 40, col: 10, offset: 744},
-				run: (*parser).callonMoveV1,
+				pos: position{line: 60, col: 21, offset: 1625},
+				run: (*parser).callonHorizontalLineTo1,
 				expr: &seqExpr{
-					pos: position{line: 40, col: 10, offset: 744},
+					pos: position{line: 60, col: 21, offset: 1625},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 40, col: 10, offset: 744},
+							pos:   position{line: 60, col: 21, offset: 1625},
 							label: "rel",
 							expr: &ruleRefExpr{
-								pos:  position{line: 40, col: 14, offset: 748},
-								name: "movev",
+								pos:  position{line: 60, col: 25, offset: 1629},
+								name: "hlineto",
 							},
 						},
-						&ruleRefExpr{
-							pos:  position{line: 40, col: 20, offset: 754},
-							name: "_",
-						},
 						&labeledExpr{
-							pos:   position{line: 40, col: 22, offset: 756},
-							label: "val",
-							expr: &ruleRefExpr{
-								pos:  position{line: 40, col: 26, offset: 760},
-								name: "Number",
+							pos:   position{line: 60, col: 33, offset: 1637},
+							label: "vals",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 60, col: 38, offset: 1642},
+								expr: &ruleRefExpr{
+									pos:  position{line: 60, col: 38, offset: 1642},
+									name: "NextNumber",
+								},
 							},
 						},
 					},
@@ -257,32 +260,31 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "LineTo",
-			pos:  position{line: 44, col: 1, offset: 851},
+			name: "VerticalLineTo",
+			pos:  position{line: 66, col: 1, offset: 1808},
 			expr: &actionExpr{
-				pos: position{line: 44, col: 11, offset: 861},
-				run: (*parser).callonLineTo1,
+				pos: position{line: 66, col: 19, offset: 1826},
+				run: (*parser).callonVerticalLineTo1,
 				expr: &seqExpr{
-					pos: position{line: 44, col: 11, offset: 861},
+					pos: position{line: 66, col: 19, offset: 1826},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 44, col: 11, offset: 861},
+							pos:   position{line: 66, col: 19, offset: 1826},
 							label: "rel",
 							expr: &ruleRefExpr{
-								pos:  position{line: 44, col: 15, offset: 865},
-								name: "lineto",
+								pos:  position{line: 66, col: 23, offset: 1830},
+								name: "vlineto",
 							},
 						},
-						&ruleRefExpr{
-							pos:  position{line: 44, col: 22, offset: 872},
-							name: "_",
-						},
 						&labeledExpr{
-							pos:   position{line: 44, col: 24, offset: 874},
-							label: "coord",
-							expr: &ruleRefExpr{
-								pos:  position{line: 44, col: 30, offset: 880},
-								name: "Coord",
+							pos:   position{line: 66, col: 31, offset: 1838},
+							label: "vals",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 66, col: 36, offset: 1843},
+								expr: &ruleRefExpr{
+									pos:  position{line: 66, col: 36, offset: 1843},
+									name: "NextNumber",
+								},
 							},
 						},
 					},
@@ -291,24 +293,24 @@ var g = &grammar{
 		},
 		{
 			name: "ClosePath",
-			pos:  position{line: 48, col: 1, offset: 959},
+			pos:  position{line: 72, col: 1, offset: 2007},
 			expr: &actionExpr{
-				pos: position{line: 48, col: 14, offset: 972},
+				pos: position{line: 72, col: 14, offset: 2020},
 				run: (*parser).callonClosePath1,
 				expr: &labeledExpr{
-					pos:   position{line: 48, col: 14, offset: 972},
+					pos:   position{line: 72, col: 14, offset: 2020},
 					label: "val",
 					expr: &choiceExpr{
-						pos: position{line: 48, col: 19, offset: 977},
+						pos: position{line: 72, col: 19, offset: 2025},
 						alternatives: []any{
 							&litMatcher{
-								pos:        position{line: 48, col: 19, offset: 977},
+								pos:        position{line: 72, col: 19, offset: 2025},
 								val:        "Z",
 								ignoreCase: false,
 								want:       "\"Z\"",
 							},
 							&litMatcher{
-								pos:        position{line: 48, col: 25, offset: 983},
+								pos:        position{line: 72, col: 25, offset: 2031},
 								val:        "z",
 								ignoreCase: false,
 								want:       "\"z\"",
@@ -320,71 +322,316 @@ var g = &grammar{
 		},
 		{
 			name: "Bezier",
-			pos:  position{line: 52, col: 1, offset: 1022},
+			pos:  position{line: 76, col: 1, offset: 2077},
 			expr: &choiceExpr{
-				pos: position{line: 52, col: 11, offset: 1032},
+				pos: position{line: 76, col: 11, offset: 2087},
 				alternatives: []any{
 					&ruleRefExpr{
-						pos:  position{line: 52, col: 11, offset: 1032},
+						pos:  position{line: 76, col: 11, offset: 2087},
 						name: "CubicBezier",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 52, col: 25, offset: 1046},
+						pos:  position{line: 76, col: 25, offset: 2101},
+						name: "SmoothCubicBezier",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 76, col: 45, offset: 2121},
 						name: "QuadraticBezier",
 					},
+					&ruleRefExpr{
+						pos:  position{line: 76, col: 63, offset: 2139},
+						name: "SmoothQuadraticBezier",
+					},
 				},
 			},
 		},
 		{
 			name: "CubicBezier",
-			pos:  position{line: 54, col: 1, offset: 1063},
+			pos:  position{line: 78, col: 1, offset: 2162},
 			expr: &actionExpr{
-				pos: position{line: 54, col: 16, offset: 1078},
+				pos: position{line: 78, col: 16, offset: 2177},
 				run: (*parser).callonCubicBezier1,
 				expr: &seqExpr{
-					pos: position{line: 54, col: 16, offset: 1078},
+					pos: position{line: 78, col: 16, offset: 2177},
 					exprs: []any{
 						&labeledExpr{
-							pos:   position{line: 54, col: 16, offset: 1078},
+							pos:   position{line: 78, col: 16, offset: 2177},
 							label: "rel",
 							expr: &ruleRefExpr{
-								pos:  position{line: 54, col: 20, offset: 1082},
+								pos:  position{line: 78, col: 20, offset: 2181},
 								name: "curve",
 							},
 						},
+						&labeledExpr{
+							pos:   position{line: 78, col: 26, offset: 2187},
+							label: "sets",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 78, col: 31, offset: 2192},
+								expr: &ruleRefExpr{
+									pos:  position{line: 78, col: 31, offset: 2192},
+									name: "NextCoordTriple",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "SmoothCubicBezier",
+			pos:  position{line: 84, col: 1, offset: 2367},
+			expr: &actionExpr{
+				pos: position{line: 84, col: 22, offset: 2388},
+				run: (*parser).callonSmoothCubicBezier1,
+				expr: &seqExpr{
+					pos: position{line: 84, col: 22, offset: 2388},
+					exprs: []any{
+						&labeledExpr{
+							pos:   position{line: 84, col: 22, offset: 2388},
+							label: "rel",
+							expr: &ruleRefExpr{
+								pos:  position{line: 84, col: 26, offset: 2392},
+								name: "scurve",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 84, col: 33, offset: 2399},
+							label: "sets",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 84, col: 38, offset: 2404},
+								expr: &ruleRefExpr{
+									pos:  position{line: 84, col: 38, offset: 2404},
+									name: "NextCoordPair",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "QuadraticBezier",
+			pos:  position{line: 90, col: 1, offset: 2583},
+			expr: &actionExpr{
+				pos: position{line: 90, col: 20, offset: 2602},
+				run: (*parser).callonQuadraticBezier1,
+				expr: &seqExpr{
+					pos: position{line: 90, col: 20, offset: 2602},
+					exprs: []any{
+						&labeledExpr{
+							pos:   position{line: 90, col: 20, offset: 2602},
+							label: "rel",
+							expr: &ruleRefExpr{
+								pos:  position{line: 90, col: 24, offset: 2606},
+								name: "qcurve",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 90, col: 31, offset: 2613},
+							label: "sets",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 90, col: 36, offset: 2618},
+								expr: &ruleRefExpr{
+									pos:  position{line: 90, col: 36, offset: 2618},
+									name: "NextCoordPair",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "SmoothQuadraticBezier",
+			pos:  position{line: 96, col: 1, offset: 2795},
+			expr: &actionExpr{
+				pos: position{line: 96, col: 26, offset: 2820},
+				run: (*parser).callonSmoothQuadraticBezier1,
+				expr: &seqExpr{
+					pos: position{line: 96, col: 26, offset: 2820},
+					exprs: []any{
+						&labeledExpr{
+							pos:   position{line: 96, col: 26, offset: 2820},
+							label: "rel",
+							expr: &ruleRefExpr{
+								pos:  position{line: 96, col: 30, offset: 2824},
+								name: "sqcurve",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 96, col: 38, offset: 2832},
+							label: "coords",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 96, col: 45, offset: 2839},
+								expr: &ruleRefExpr{
+									pos:  position{line: 96, col: 45, offset: 2839},
+									name: "NextCoord",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Arc",
+			pos:  position{line: 102, col: 1, offset: 3015},
+			expr: &actionExpr{
+				pos: position{line: 102, col: 8, offset: 3022},
+				run: (*parser).callonArc1,
+				expr: &seqExpr{
+					pos: position{line: 102, col: 8, offset: 3022},
+					exprs: []any{
+						&labeledExpr{
+							pos:   position{line: 102, col: 8, offset: 3022},
+							label: "rel",
+							expr: &ruleRefExpr{
+								pos:  position{line: 102, col: 12, offset: 3026},
+								name: "arc",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 102, col: 16, offset: 3030},
+							label: "arcs",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 102, col: 21, offset: 3035},
+								expr: &ruleRefExpr{
+									pos:  position{line: 102, col: 21, offset: 3035},
+									name: "NextArc",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "NextArc",
+			pos:  position{line: 109, col: 1, offset: 3182},
+			expr: &actionExpr{
+				pos: position{line: 109, col: 12, offset: 3193},
+				run: (*parser).callonNextArc1,
+				expr: &seqExpr{
+					pos: position{line: 109, col: 12, offset: 3193},
+					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 54, col: 26, offset: 1088},
-							name: "_",
+							pos:  position{line: 109, col: 12, offset: 3193},
+							name: "sep",
+						},
+						&labeledExpr{
+							pos:   position{line: 109, col: 16, offset: 3197},
+							label: "rx",
+							expr: &ruleRefExpr{
+								pos:  position{line: 109, col: 19, offset: 3200},
+								name: "Number",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 109, col: 26, offset: 3207},
+							name: "sep",
+						},
+						&labeledExpr{
+							pos:   position{line: 109, col: 30, offset: 3211},
+							label: "ry",
+							expr: &ruleRefExpr{
+								pos:  position{line: 109, col: 33, offset: 3214},
+								name: "Number",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 109, col: 40, offset: 3221},
+							name: "sep",
+						},
+						&labeledExpr{
+							pos:   position{line: 109, col: 44, offset: 3225},
+							label: "rot",
+							expr: &ruleRefExpr{
+								pos:  position{line: 109, col: 48, offset: 3229},
+								name: "Number",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 109, col: 55, offset: 3236},
+							name: "sep",
+						},
+						&labeledExpr{
+							pos:   position{line: 109, col: 59, offset: 3240},
+							label: "large",
+							expr: &ruleRefExpr{
+								pos:  position{line: 109, col: 65, offset: 3246},
+								name: "Flag",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 109, col: 70, offset: 3251},
+							name: "sep",
+						},
+						&labeledExpr{
+							pos:   position{line: 109, col: 74, offset: 3255},
+							label: "sweep",
+							expr: &ruleRefExpr{
+								pos:  position{line: 109, col: 80, offset: 3261},
+								name: "Flag",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 109, col: 85, offset: 3266},
+							name: "sep",
 						},
 						&labeledExpr{
-							pos:   position{line: 54, col: 28, offset: 1090},
+							pos:   position{line: 109, col: 89, offset: 3270},
+							label: "end",
+							expr: &ruleRefExpr{
+								pos:  position{line: 109, col: 93, offset: 3274},
+								name: "Coord",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "NextCoordTriple",
+			pos:  position{line: 119, col: 1, offset: 3494},
+			expr: &actionExpr{
+				pos: position{line: 119, col: 20, offset: 3513},
+				run: (*parser).callonNextCoordTriple1,
+				expr: &seqExpr{
+					pos: position{line: 119, col: 20, offset: 3513},
+					exprs: []any{
+						&ruleRefExpr{
+							pos:  position{line: 119, col: 20, offset: 3513},
+							name: "sep",
+						},
+						&labeledExpr{
+							pos:   position{line: 119, col: 24, offset: 3517},
 							label: "c1",
 							expr: &ruleRefExpr{
-								pos:  position{line: 54, col: 31, offset: 1093},
+								pos:  position{line: 119, col: 27, offset: 3520},
 								name: "Coord",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 54, col: 37, offset: 1099},
-							name: "_",
+							pos:  position{line: 119, col: 33, offset: 3526},
+							name: "sep",
 						},
 						&labeledExpr{
-							pos:   position{line: 54, col: 39, offset: 1101},
+							pos:   position{line: 119, col: 37, offset: 3530},
 							label: "c2",
 							expr: &ruleRefExpr{
-								pos:  position{line: 54, col: 42, offset: 1104},
+								pos:  position{line: 119, col: 40, offset: 3533},
 								name: "Coord",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 54, col: 48, offset: 1110},
-							name: "_",
+							pos:  position{line: 119, col: 46, offset: 3539},
+							name: "sep",
 						},
 						&labeledExpr{
-							pos:   position{line: 54, col: 50, offset: 1112},
+							pos:   position{line: 119, col: 50, offset: 3543},
 							label: "c3",
 							expr: &ruleRefExpr{
-								pos:  position{line: 54, col: 53, offset: 1115},
+								pos:  position{line: 119, col: 53, offset: 3546},
 								name: "Coord",
 							},
 						},
@@ -393,39 +640,35 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "QuadraticBezier",
-			pos:  position{line: 58, col: 1, offset: 1230},
+			name: "NextCoordPair",
+			pos:  position{line: 123, col: 1, offset: 3616},
 			expr: &actionExpr{
-				pos: position{line: 58, col: 20, offset: 1249},
-				run: (*parser).callonQuadraticBezier1,
+				pos: position{line: 123, col: 18, offset: 3633},
+				run: (*parser).callonNextCoordPair1,
 				expr: &seqExpr{
-					pos: position{line: 58, col: 20, offset: 1249},
+					pos: position{line: 123, col: 18, offset: 3633},
 					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 58, col: 20, offset: 1249},
-							name: "qcurve",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 58, col: 27, offset: 1256},
-							name: "_",
+							pos:  position{line: 123, col: 18, offset: 3633},
+							name: "sep",
 						},
 						&labeledExpr{
-							pos:   position{line: 58, col: 29, offset: 1258},
+							pos:   position{line: 123, col: 22, offset: 3637},
 							label: "c1",
 							expr: &ruleRefExpr{
-								pos:  position{line: 58, col: 32, offset: 1261},
+								pos:  position{line: 123, col: 25, offset: 3640},
 								name: "Coord",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 58, col: 38, offset: 1267},
-							name: "_",
+							pos:  position{line: 123, col: 31, offset: 3646},
+							name: "sep",
 						},
 						&labeledExpr{
-							pos:   position{line: 58, col: 40, offset: 1269},
+							pos:   position{line: 123, col: 35, offset: 3650},
 							label: "c2",
 							expr: &ruleRefExpr{
-								pos:  position{line: 58, col: 43, offset: 1272},
+								pos:  position{line: 123, col: 38, offset: 3653},
 								name: "Coord",
 							},
 						},
@@ -434,44 +677,96 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "Coord",
-			pos:  position{line: 63, col: 1, offset: 1427},
+			name: "NextCoord",
+			pos:  position{line: 128, col: 1, offset: 3775},
 			expr: &actionExpr{
-				pos: position{line: 63, col: 10, offset: 1436},
-				run: (*parser).callonCoord1,
+				pos: position{line: 128, col: 14, offset: 3788},
+				run: (*parser).callonNextCoord1,
 				expr: &seqExpr{
-					pos: position{line: 63, col: 10, offset: 1436},
+					pos: position{line: 128, col: 14, offset: 3788},
 					exprs: []any{
+						&ruleRefExpr{
+							pos:  position{line: 128, col: 14, offset: 3788},
+							name: "sep",
+						},
 						&labeledExpr{
-							pos:   position{line: 63, col: 10, offset: 1436},
-							label: "x",
+							pos:   position{line: 128, col: 18, offset: 3792},
+							label: "coord",
 							expr: &ruleRefExpr{
-								pos:  position{line: 63, col: 12, offset: 1438},
-								name: "Number",
+								pos:  position{line: 128, col: 24, offset: 3798},
+								name: "Coord",
 							},
 						},
+					},
+				},
+			},
+		},
+		{
+			name: "NextNumber",
+			pos:  position{line: 132, col: 1, offset: 3831},
+			expr: &actionExpr{
+				pos: position{line: 132, col: 15, offset: 3845},
+				run: (*parser).callonNextNumber1,
+				expr: &seqExpr{
+					pos: position{line: 132, col: 15, offset: 3845},
+					exprs: []any{
 						&ruleRefExpr{
-							pos:  position{line: 63, col: 19, offset: 1445},
-							name: "_",
+							pos:  position{line: 132, col: 15, offset: 3845},
+							name: "sep",
 						},
-						&zeroOrOneExpr{
-							pos: position{line: 63, col: 21, offset: 1447},
-							expr: &litMatcher{
-								pos:        position{line: 63, col: 21, offset: 1447},
-								val:        ",",
-								ignoreCase: false,
-								want:       "\",\"",
+						&labeledExpr{
+							pos:   position{line: 132, col: 19, offset: 3849},
+							label: "val",
+							expr: &ruleRefExpr{
+								pos:  position{line: 132, col: 23, offset: 3853},
+								name: "Number",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Flag",
+			pos:  position{line: 137, col: 1, offset: 3978},
+			expr: &actionExpr{
+				pos: position{line: 137, col: 9, offset: 3986},
+				run: (*parser).callonFlag1,
+				expr: &charClassMatcher{
+					pos:        position{line: 137, col: 9, offset: 3986},
+					val:        "[01]",
+					chars:      []rune{'0', '1'},
+					ignoreCase: false,
+					inverted:   false,
+				},
+			},
+		},
+		{
+			name: "Coord",
+			pos:  position{line: 141, col: 1, offset: 4029},
+			expr: &actionExpr{
+				pos: position{line: 141, col: 10, offset: 4038},
+				run: (*parser).callonCoord1,
+				expr: &seqExpr{
+					pos: position{line: 141, col: 10, offset: 4038},
+					exprs: []any{
+						&labeledExpr{
+							pos:   position{line: 141, col: 10, offset: 4038},
+							label: "x",
+							expr: &ruleRefExpr{
+								pos:  position{line: 141, col: 12, offset: 4040},
+								name: "Number",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 63, col: 26, offset: 1452},
-							name: "_",
+							pos:  position{line: 141, col: 19, offset: 4047},
+							name: "sep",
 						},
 						&labeledExpr{
-							pos:   position{line: 63, col: 28, offset: 1454},
+							pos:   position{line: 141, col: 23, offset: 4051},
 							label: "y",
 							expr: &ruleRefExpr{
-								pos:  position{line: 63, col: 30, offset: 1456},
+								pos:  position{line: 141, col: 25, offset: 4053},
 								name: "Number",
 							},
 						},
@@ -481,15 +776,15 @@ var g = &grammar{
 		},
 		{
 			name: "Number",
-			pos:  position{line: 67, col: 1, offset: 1514},
+			pos:  position{line: 145, col: 1, offset: 4113},
 			expr: &actionExpr{
-				pos: position{line: 67, col: 11, offset: 1524},
+				pos: position{line: 145, col: 11, offset: 4123},
 				run: (*parser).callonNumber1,
 				expr: &labeledExpr{
-					pos:   position{line: 67, col: 11, offset: 1524},
+					pos:   position{line: 145, col: 11, offset: 4123},
 					label: "val",
 					expr: &ruleRefExpr{
-						pos:  position{line: 67, col: 15, offset: 1528},
+						pos:  position{line: 145, col: 15, offset: 4127},
 						name: "number",
 					},
 				},
@@ -497,70 +792,193 @@ var g = &grammar{
 		},
 		{
 			name: "number",
-			pos:  position{line: 71, col: 1, offset: 1571},
+			pos:  position{line: 151, col: 1, offset: 4406},
 			expr: &seqExpr{
-				pos: position{line: 71, col: 11, offset: 1581},
+				pos: position{line: 151, col: 11, offset: 4416},
 				exprs: []any{
 					&zeroOrOneExpr{
-						pos: position{line: 71, col: 11, offset: 1581},
-						expr: &litMatcher{
-							pos:        position{line: 71, col: 11, offset: 1581},
-							val:        "-",
-							ignoreCase: false,
-							want:       "\"-\"",
-						},
-					},
-					&oneOrMoreExpr{
-						pos: position{line: 71, col: 16, offset: 1586},
+						pos: position{line: 151, col: 11, offset: 4416},
 						expr: &ruleRefExpr{
-							pos:  position{line: 71, col: 16, offset: 1586},
-							name: "digit",
+							pos:  position{line: 151, col: 11, offset: 4416},
+							name: "sign",
 						},
 					},
-					&zeroOrOneExpr{
-						pos: position{line: 71, col: 23, offset: 1593},
-						expr: &seqExpr{
-							pos: position{line: 71, col: 24, offset: 1594},
-							exprs: []any{
-								&litMatcher{
-									pos:        position{line: 71, col: 24, offset: 1594},
-									val:        ".",
-									ignoreCase: false,
-									want:       "\".\"",
+					&choiceExpr{
+						pos: position{line: 151, col: 18, offset: 4423},
+						alternatives: []any{
+							&seqExpr{
+								pos: position{line: 151, col: 18, offset: 4423},
+								exprs: []any{
+									&ruleRefExpr{
+										pos:  position{line: 151, col: 18, offset: 4423},
+										name: "fractional",
+									},
+									&zeroOrOneExpr{
+										pos: position{line: 151, col: 29, offset: 4434},
+										expr: &ruleRefExpr{
+											pos:  position{line: 151, col: 29, offset: 4434},
+											name: "exponent",
+										},
+									},
 								},
-								&oneOrMoreExpr{
-									pos: position{line: 71, col: 28, offset: 1598},
-									expr: &ruleRefExpr{
-										pos:  position{line: 71, col: 28, offset: 1598},
-										name: "digit",
+							},
+							&seqExpr{
+								pos: position{line: 151, col: 41, offset: 4446},
+								exprs: []any{
+									&oneOrMoreExpr{
+										pos: position{line: 151, col: 41, offset: 4446},
+										expr: &ruleRefExpr{
+											pos:  position{line: 151, col: 41, offset: 4446},
+											name: "digit",
+										},
 									},
+									&zeroOrOneExpr{
+										pos: position{line: 151, col: 48, offset: 4453},
+										expr: &ruleRefExpr{
+											pos:  position{line: 151, col: 48, offset: 4453},
+											name: "exponent",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "fractional",
+			pos:  position{line: 153, col: 1, offset: 4465},
+			expr: &choiceExpr{
+				pos: position{line: 153, col: 15, offset: 4479},
+				alternatives: []any{
+					&seqExpr{
+						pos: position{line: 153, col: 15, offset: 4479},
+						exprs: []any{
+							&zeroOrMoreExpr{
+								pos: position{line: 153, col: 15, offset: 4479},
+								expr: &ruleRefExpr{
+									pos:  position{line: 153, col: 15, offset: 4479},
+									name: "digit",
 								},
 							},
+							&litMatcher{
+								pos:        position{line: 153, col: 22, offset: 4486},
+								val:        ".",
+								ignoreCase: false,
+								want:       "\".\"",
+							},
+							&oneOrMoreExpr{
+								pos: position{line: 153, col: 26, offset: 4490},
+								expr: &ruleRefExpr{
+									pos:  position{line: 153, col: 26, offset: 4490},
+									name: "digit",
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 153, col: 35, offset: 4499},
+						exprs: []any{
+							&oneOrMoreExpr{
+								pos: position{line: 153, col: 35, offset: 4499},
+								expr: &ruleRefExpr{
+									pos:  position{line: 153, col: 35, offset: 4499},
+									name: "digit",
+								},
+							},
+							&litMatcher{
+								pos:        position{line: 153, col: 42, offset: 4506},
+								val:        ".",
+								ignoreCase: false,
+								want:       "\".\"",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "exponent",
+			pos:  position{line: 155, col: 1, offset: 4511},
+			expr: &seqExpr{
+				pos: position{line: 155, col: 13, offset: 4523},
+				exprs: []any{
+					&choiceExpr{
+						pos: position{line: 155, col: 14, offset: 4524},
+						alternatives: []any{
+							&litMatcher{
+								pos:        position{line: 155, col: 14, offset: 4524},
+								val:        "e",
+								ignoreCase: false,
+								want:       "\"e\"",
+							},
+							&litMatcher{
+								pos:        position{line: 155, col: 20, offset: 4530},
+								val:        "E",
+								ignoreCase: false,
+								want:       "\"E\"",
+							},
+						},
+					},
+					&zeroOrOneExpr{
+						pos: position{line: 155, col: 25, offset: 4535},
+						expr: &ruleRefExpr{
+							pos:  position{line: 155, col: 25, offset: 4535},
+							name: "sign",
+						},
+					},
+					&oneOrMoreExpr{
+						pos: position{line: 155, col: 31, offset: 4541},
+						expr: &ruleRefExpr{
+							pos:  position{line: 155, col: 31, offset: 4541},
+							name: "digit",
 						},
 					},
 				},
 			},
 		},
+		{
+			name: "sign",
+			pos:  position{line: 157, col: 1, offset: 4549},
+			expr: &choiceExpr{
+				pos: position{line: 157, col: 9, offset: 4557},
+				alternatives: []any{
+					&litMatcher{
+						pos:        position{line: 157, col: 9, offset: 4557},
+						val:        "+",
+						ignoreCase: false,
+						want:       "\"+\"",
+					},
+					&litMatcher{
+						pos:        position{line: 157, col: 15, offset: 4563},
+						val:        "-",
+						ignoreCase: false,
+						want:       "\"-\"",
+					},
+				},
+			},
+		},
 		{
 			name: "move",
-			pos:  position{line: 73, col: 1, offset: 1608},
+			pos:  position{line: 160, col: 1, offset: 4569},
 			expr: &actionExpr{
-				pos: position{line: 73, col: 9, offset: 1616},
+				pos: position{line: 160, col: 9, offset: 4577},
 				run: (*parser).callonmove1,
 				expr: &labeledExpr{
-					pos:   position{line: 73, col: 9, offset: 1616},
+					pos:   position{line: 160, col: 9, offset: 4577},
 					label: "val",
 					expr: &choiceExpr{
-						pos: position{line: 73, col: 14, offset: 1621},
+						pos: position{line: 160, col: 14, offset: 4582},
 						alternatives: []any{
 							&litMatcher{
-								pos:        position{line: 73, col: 14, offset: 1621},
+								pos:        position{line: 160, col: 14, offset: 4582},
 								val:        "M",
 								ignoreCase: false,
 								want:       "\"M\"",
 							},
 							&litMatcher{
-								pos:        position{line: 73, col: 20, offset: 1627},
+								pos:        position{line: 160, col: 20, offset: 4588},
 								val:        "m",
 								ignoreCase: false,
 								want:       "\"m\"",
@@ -572,24 +990,24 @@ var g = &grammar{
 		},
 		{
 			name: "lineto",
-			pos:  position{line: 75, col: 1, offset: 1663},
+			pos:  position{line: 162, col: 1, offset: 4624},
 			expr: &actionExpr{
-				pos: position{line: 75, col: 11, offset: 1673},
+				pos: position{line: 162, col: 11, offset: 4634},
 				run: (*parser).callonlineto1,
 				expr: &labeledExpr{
-					pos:   position{line: 75, col: 11, offset: 1673},
+					pos:   position{line: 162, col: 11, offset: 4634},
 					label: "val",
 					expr: &choiceExpr{
-						pos: position{line: 75, col: 16, offset: 1678},
+						pos: position{line: 162, col: 16, offset: 4639},
 						alternatives: []any{
 							&litMatcher{
-								pos:        position{line: 75, col: 16, offset: 1678},
+								pos:        position{line: 162, col: 16, offset: 4639},
 								val:        "L",
 								ignoreCase: false,
 								want:       "\"L\"",
 							},
 							&litMatcher{
-								pos:        position{line: 75, col: 22, offset: 1684},
+								pos:        position{line: 162, col: 22, offset: 4645},
 								val:        "l",
 								ignoreCase: false,
 								want:       "\"l\"",
@@ -601,24 +1019,24 @@ var g = &grammar{
 		},
 		{
 			name: "curve",
-			pos:  position{line: 77, col: 1, offset: 1720},
+			pos:  position{line: 164, col: 1, offset: 4681},
 			expr: &actionExpr{
-				pos: position{line: 77, col: 10, offset: 1729},
+				pos: position{line: 164, col: 10, offset: 4690},
 				run: (*parser).calloncurve1,
 				expr: &labeledExpr{
-					pos:   position{line: 77, col: 10, offset: 1729},
+					pos:   position{line: 164, col: 10, offset: 4690},
 					label: "val",
 					expr: &choiceExpr{
-						pos: position{line: 77, col: 15, offset: 1734},
+						pos: position{line: 164, col: 15, offset: 4695},
 						alternatives: []any{
 							&litMatcher{
-								pos:        position{line: 77, col: 15, offset: 1734},
+								pos:        position{line: 164, col: 15, offset: 4695},
 								val:        "C",
 								ignoreCase: false,
 								want:       "\"C\"",
 							},
 							&litMatcher{
-								pos:        position{line: 77, col: 21, offset: 1740},
+								pos:        position{line: 164, col: 21, offset: 4701},
 								val:        "c",
 								ignoreCase: false,
 								want:       "\"c\"",
@@ -629,25 +1047,54 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "moveh",
-			pos:  position{line: 79, col: 1, offset: 1776},
+			name: "scurve",
+			pos:  position{line: 166, col: 1, offset: 4737},
+			expr: &actionExpr{
+				pos: position{line: 166, col: 11, offset: 4747},
+				run: (*parser).callonscurve1,
+				expr: &labeledExpr{
+					pos:   position{line: 166, col: 11, offset: 4747},
+					label: "val",
+					expr: &choiceExpr{
+						pos: position{line: 166, col: 16, offset: 4752},
+						alternatives: []any{
+							&litMatcher{
+								pos:        position{line: 166, col: 16, offset: 4752},
+								val:        "S",
+								ignoreCase: false,
+								want:       "\"S\"",
+							},
+							&litMatcher{
+								pos:        position{line: 166, col: 22, offset: 4758},
+								val:        "s",
+								ignoreCase: false,
+								want:       "\"s\"",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "hlineto",
+			pos:  position{line: 168, col: 1, offset: 4794},
 			expr: &actionExpr{
-				pos: position{line: 79, col: 10, offset: 1785},
-				run: (*parser).callonmoveh1,
+				pos: position{line: 168, col: 12, offset: 4805},
+				run: (*parser).callonhlineto1,
 				expr: &labeledExpr{
-					pos:   position{line: 79, col: 10, offset: 1785},
+					pos:   position{line: 168, col: 12, offset: 4805},
 					label: "val",
 					expr: &choiceExpr{
-						pos: position{line: 79, col: 15, offset: 1790},
+						pos: position{line: 168, col: 17, offset: 4810},
 						alternatives: []any{
 							&litMatcher{
-								pos:        position{line: 79, col: 15, offset: 1790},
+								pos:        position{line: 168, col: 17, offset: 4810},
 								val:        "H",
 								ignoreCase: false,
 								want:       "\"H\"",
 							},
 							&litMatcher{
-								pos:        position{line: 79, col: 21, offset: 1796},
+								pos:        position{line: 168, col: 23, offset: 4816},
 								val:        "h",
 								ignoreCase: false,
 								want:       "\"h\"",
@@ -658,25 +1105,25 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "movev",
-			pos:  position{line: 81, col: 1, offset: 1832},
+			name: "vlineto",
+			pos:  position{line: 170, col: 1, offset: 4852},
 			expr: &actionExpr{
-				pos: position{line: 81, col: 10, offset: 1841},
-				run: (*parser).callonmovev1,
+				pos: position{line: 170, col: 12, offset: 4863},
+				run: (*parser).callonvlineto1,
 				expr: &labeledExpr{
-					pos:   position{line: 81, col: 10, offset: 1841},
+					pos:   position{line: 170, col: 12, offset: 4863},
 					label: "val",
 					expr: &choiceExpr{
-						pos: position{line: 81, col: 15, offset: 1846},
+						pos: position{line: 170, col: 17, offset: 4868},
 						alternatives: []any{
 							&litMatcher{
-								pos:        position{line: 81, col: 15, offset: 1846},
+								pos:        position{line: 170, col: 17, offset: 4868},
 								val:        "V",
 								ignoreCase: false,
 								want:       "\"V\"",
 							},
 							&litMatcher{
-								pos:        position{line: 81, col: 21, offset: 1852},
+								pos:        position{line: 170, col: 23, offset: 4874},
 								val:        "v",
 								ignoreCase: false,
 								want:       "\"v\"",
@@ -688,24 +1135,24 @@ var g = &grammar{
 		},
 		{
 			name: "qcurve",
-			pos:  position{line: 83, col: 1, offset: 1888},
+			pos:  position{line: 172, col: 1, offset: 4910},
 			expr: &actionExpr{
-				pos: position{line: 83, col: 11, offset: 1898},
+				pos: position{line: 172, col: 11, offset: 4920},
 				run: (*parser).callonqcurve1,
 				expr: &labeledExpr{
-					pos:   position{line: 83, col: 11, offset: 1898},
+					pos:   position{line: 172, col: 11, offset: 4920},
 					label: "val",
 					expr: &choiceExpr{
-						pos: position{line: 83, col: 16, offset: 1903},
+						pos: position{line: 172, col: 16, offset: 4925},
 						alternatives: []any{
 							&litMatcher{
-								pos:        position{line: 83, col: 16, offset: 1903},
+								pos:        position{line: 172, col: 16, offset: 4925},
 								val:        "Q",
 								ignoreCase: false,
 								want:       "\"Q\"",
 							},
 							&litMatcher{
-								pos:        position{line: 83, col: 22, offset: 1909},
+								pos:        position{line: 172, col: 22, offset: 4931},
 								val:        "q",
 								ignoreCase: false,
 								want:       "\"q\"",
@@ -715,28 +1162,126 @@ var g = &grammar{
 				},
 			},
 		},
+		{
+			name: "sqcurve",
+			pos:  position{line: 174, col: 1, offset: 4967},
+			expr: &actionExpr{
+				pos: position{line: 174, col: 12, offset: 4978},
+				run: (*parser).callonsqcurve1,
+				expr: &labeledExpr{
+					pos:   position{line: 174, col: 12, offset: 4978},
+					label: "val",
+					expr: &choiceExpr{
+						pos: position{line: 174, col: 17, offset: 4983},
+						alternatives: []any{
+							&litMatcher{
+								pos:        position{line: 174, col: 17, offset: 4983},
+								val:        "T",
+								ignoreCase: false,
+								want:       "\"T\"",
+							},
+							&litMatcher{
+								pos:        position{line: 174, col: 23, offset: 4989},
+								val:        "t",
+								ignoreCase: false,
+								want:       "\"t\"",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "arc",
+			pos:  position{line: 176, col: 1, offset: 5025},
+			expr: &actionExpr{
+				pos: position{line: 176, col: 8, offset: 5032},
+				run: (*parser).callonarc1,
+				expr: &labeledExpr{
+					pos:   position{line: 176, col: 8, offset: 5032},
+					label: "val",
+					expr: &choiceExpr{
+						pos: position{line: 176, col: 13, offset: 5037},
+						alternatives: []any{
+							&litMatcher{
+								pos:        position{line: 176, col: 13, offset: 5037},
+								val:        "A",
+								ignoreCase: false,
+								want:       "\"A\"",
+							},
+							&litMatcher{
+								pos:        position{line: 176, col: 19, offset: 5043},
+								val:        "a",
+								ignoreCase: false,
+								want:       "\"a\"",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "digit",
-			pos:  position{line: 85, col: 1, offset: 1945},
+			pos:  position{line: 178, col: 1, offset: 5079},
 			expr: &charClassMatcher{
-				pos:        position{line: 85, col: 10, offset: 1954},
+				pos:        position{line: 178, col: 10, offset: 5088},
 				val:        "[0-9]",
 				ranges:     []rune{'0', '9'},
 				ignoreCase: false,
 				inverted:   false,
 			},
 		},
+		{
+			name: "sep",
+			pos:  position{line: 180, col: 1, offset: 5095},
+			expr: &seqExpr{
+				pos: position{line: 180, col: 8, offset: 5102},
+				exprs: []any{
+					&ruleRefExpr{
+						pos:  position{line: 180, col: 8, offset: 5102},
+						name: "_",
+					},
+					&zeroOrOneExpr{
+						pos: position{line: 180, col: 10, offset: 5104},
+						expr: &litMatcher{
+							pos:        position{line: 180, col: 10, offset: 5104},
+							val:        ",",
+							ignoreCase: false,
+							want:       "\",\"",
+						},
+					},
+					&ruleRefExpr{
+						pos:  position{line: 180, col: 15, offset: 5109},
+						name: "_",
+					},
+				},
+			},
+		},
+		{
+			name: "Rest",
+			pos:  position{line: 182, col: 1, offset: 5112},
+			expr: &actionExpr{
+				pos: position{line: 182, col: 9, offset: 5120},
+				run: (*parser).callonRest1,
+				expr: &zeroOrMoreExpr{
+					pos: position{line: 182, col: 9, offset: 5120},
+					expr: &anyMatcher{
+						line: 182, col: 9, offset: 5120,
+					},
+				},
+			},
+		},
 		{
 			name:        "_",
 			displayName: "\"whitespace\"",
-			pos:         position{line: 87, col: 1, offset: 1961},
+			pos:         position{line: 186, col: 1, offset: 5159},
 			expr: &actionExpr{
-				pos: position{line: 87, col: 19, offset: 1979},
+				pos: position{line: 186, col: 19, offset: 5177},
 				run: (*parser).callon_1,
 				expr: &zeroOrMoreExpr{
-					pos: position{line: 87, col: 19, offset: 1979},
+					pos: position{line: 186, col: 19, offset: 5177},
 					expr: &charClassMatcher{
-						pos:        position{line: 87, col: 19, offset: 1979},
+						pos:        position{line: 186, col: 19, offset: 5177},
 						val:        "[ \\t\\r\\n]",
 						chars:      []rune{' ', '\t', '\r', '\n'},
 						ignoreCase: false,
@@ -748,14 +1293,18 @@ var g = &grammar{
 	},
 }
 
-func (c *current) onPath1(curve any) (any, error) {
-	return &Path{Children: curve}, nil
+func (c *current) onPath1(curve, rest any) (any, error) {
+	children := CommandList{}
+	if curve != nil {
+		children = curve.(CommandList)
+	}
+	return &Path{Children: children, Unparsed: rest.(string)}, nil
 }
 
 func (p *parser) callonPath1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onPath1(stack["curve"])
+	return p.cur.onPath1(stack["curve"], stack["rest"])
 }
 
 func (c *current) onSeq1(seq any) (any, error) {
@@ -769,7 +1318,12 @@ func (p *parser) callonSeq1() (any, error) {
 }
 
 func (c *current) onCurve1(cmds any) (any, error) {
-	return CommandList(std.TypedSlice[any](cmds)), nil
+	// Each command letter can be followed by several sets of arguments, and so yields a list of commands
+	list := CommandList{}
+	for _, cmd := range std.TypedSlice[[]any](cmds) {
+		list = append(list, cmd...)
+	}
+	return list, nil
 }
 
 func (p *parser) callonCurve1() (any, error) {
@@ -788,48 +1342,63 @@ func (p *parser) callonCommand1() (any, error) {
 	return p.cur.onCommand1(stack["val"])
 }
 
-func (c *current) onMoveTo1(rel, coord any) (any, error) {
-	return &MoveTo{Coord: coord.(Coord), Relative: rel.(bool)}, nil
+func (c *current) onMoveTo1(rel, coords any) (any, error) {
+	// Coordinate pairs after the first are implicit linetos, relative if the moveto is
+	cmds := []any{}
+	for i, coord := range std.TypedSlice[Coord](coords) {
+		if i == 0 {
+			cmds = append(cmds, &MoveTo{Coord: coord, Relative: rel.(bool)})
+		} else {
+			cmds = append(cmds, &LineTo{Coord: coord, Relative: rel.(bool)})
+		}
+	}
+	return cmds, nil
 }
 
 func (p *parser) callonMoveTo1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMoveTo1(stack["rel"], stack["coord"])
+	return p.cur.onMoveTo1(stack["rel"], stack["coords"])
 }
 
-func (c *current) onMoveH1(rel, val any) (any, error) {
-	return &MoveTo{Coord: Coord{val.(string), "0"}, Relative: rel.(bool)}, nil
+func (c *current) onLineTo1(rel, coords any) (any, error) {
+	return std.Map(std.TypedSlice[Coord](coords), func(coord Coord) any {
+		return &LineTo{Coord: coord, Relative: rel.(bool)}
+	}), nil
 }
 
-func (p *parser) callonMoveH1() (any, error) {
+func (p *parser) callonLineTo1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMoveH1(stack["rel"], stack["val"])
+	return p.cur.onLineTo1(stack["rel"], stack["coords"])
 }
 
-func (c *current) onMoveV1(rel, val any) (any, error) {
-	return &MoveTo{Coord: Coord{"0", val.(string)}, Relative: rel.(bool)}, nil
+func (c *current) onHorizontalLineTo1(rel, vals any) (any, error) {
+	return std.Map(std.TypedSlice[float64](vals), func(val float64) any {
+		return &HorizontalLineTo{X: val, Relative: rel.(bool)}
+	}), nil
 }
 
-func (p *parser) callonMoveV1() (any, error) {
+func (p *parser) callonHorizontalLineTo1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMoveV1(stack["rel"], stack["val"])
+	return p.cur.onHorizontalLineTo1(stack["rel"], stack["vals"])
 }
 
-func (c *current) onLineTo1(rel, coord any) (any, error) {
-	return &LineTo{Coord: coord.(Coord), Relative: rel.(bool)}, nil
+func (c *current) onVerticalLineTo1(rel, vals any) (any, error) {
+	return std.Map(std.TypedSlice[float64](vals), func(val float64) any {
+		return &VerticalLineTo{Y: val, Relative: rel.(bool)}
+	}), nil
 }
 
-func (p *parser) callonLineTo1() (any, error) {
+func (p *parser) callonVerticalLineTo1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onLineTo1(stack["rel"], stack["coord"])
+	return p.cur.onVerticalLineTo1(stack["rel"], stack["vals"])
 }
 
 func (c *current) onClosePath1(val any) (any, error) {
-	return &ClosePath{}, nil
+	return []any{&ClosePath{}}, nil
 }
 
 func (p *parser) callonClosePath1() (any, error) {
@@ -838,29 +1407,135 @@ func (p *parser) callonClosePath1() (any, error) {
 	return p.cur.onClosePath1(stack["val"])
 }
 
-func (c *current) onCubicBezier1(rel, c1, c2, c3 any) (any, error) {
-	return &CubicBezier{Points: []Coord{c1.(Coord), c2.(Coord), c3.(Coord)}, Relative: rel.(bool)}, nil
+func (c *current) onCubicBezier1(rel, sets any) (any, error) {
+	return std.Map(std.TypedSlice[Coords](sets), func(points Coords) any {
+		return &CubicBezier{Points: points, Relative: rel.(bool)}
+	}), nil
 }
 
 func (p *parser) callonCubicBezier1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onCubicBezier1(stack["rel"], stack["c1"], stack["c2"], stack["c3"])
+	return p.cur.onCubicBezier1(stack["rel"], stack["sets"])
 }
 
-func (c *current) onQuadraticBezier1(c1, c2 any) (any, error) {
-	// return &QuadraticBezier{Points: []Coord{c1.(Coord), c2.(Coord)}}, nil
-	return nil, fmt.Errorf("quadratic curves not implemented yet")
+func (c *current) onSmoothCubicBezier1(rel, sets any) (any, error) {
+	return std.Map(std.TypedSlice[Coords](sets), func(points Coords) any {
+		return &SmoothCubicBezier{Points: points, Relative: rel.(bool)}
+	}), nil
+}
+
+func (p *parser) callonSmoothCubicBezier1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onSmoothCubicBezier1(stack["rel"], stack["sets"])
+}
+
+func (c *current) onQuadraticBezier1(rel, sets any) (any, error) {
+	return std.Map(std.TypedSlice[Coords](sets), func(points Coords) any {
+		return &QuadraticBezier{Points: points, Relative: rel.(bool)}
+	}), nil
 }
 
 func (p *parser) callonQuadraticBezier1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onQuadraticBezier1(stack["c1"], stack["c2"])
+	return p.cur.onQuadraticBezier1(stack["rel"], stack["sets"])
+}
+
+func (c *current) onSmoothQuadraticBezier1(rel, coords any) (any, error) {
+	return std.Map(std.TypedSlice[Coord](coords), func(coord Coord) any {
+		return &SmoothQuadraticBezier{Coord: coord, Relative: rel.(bool)}
+	}), nil
+}
+
+func (p *parser) callonSmoothQuadraticBezier1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onSmoothQuadraticBezier1(stack["rel"], stack["coords"])
+}
+
+func (c *current) onArc1(rel, arcs any) (any, error) {
+	return std.Map(std.TypedSlice[*Arc](arcs), func(arc *Arc) any {
+		arc.Relative = rel.(bool)
+		return arc
+	}), nil
+}
+
+func (p *parser) callonArc1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArc1(stack["rel"], stack["arcs"])
+}
+
+func (c *current) onNextArc1(rx, ry, rot, large, sweep, end any) (any, error) {
+	return &Arc{
+		Radii:    Coord{rx.(float64), ry.(float64)},
+		Rotation: rot.(float64),
+		LargeArc: large.(bool),
+		Sweep:    sweep.(bool),
+		Coord:    end.(Coord),
+	}, nil
+}
+
+func (p *parser) callonNextArc1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNextArc1(stack["rx"], stack["ry"], stack["rot"], stack["large"], stack["sweep"], stack["end"])
+}
+
+func (c *current) onNextCoordTriple1(c1, c2, c3 any) (any, error) {
+	return Coords{c1.(Coord), c2.(Coord), c3.(Coord)}, nil
+}
+
+func (p *parser) callonNextCoordTriple1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNextCoordTriple1(stack["c1"], stack["c2"], stack["c3"])
+}
+
+func (c *current) onNextCoordPair1(c1, c2 any) (any, error) {
+	return Coords{c1.(Coord), c2.(Coord)}, nil
+}
+
+func (p *parser) callonNextCoordPair1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNextCoordPair1(stack["c1"], stack["c2"])
+}
+
+func (c *current) onNextCoord1(coord any) (any, error) {
+	return coord, nil
+}
+
+func (p *parser) callonNextCoord1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNextCoord1(stack["coord"])
+}
+
+func (c *current) onNextNumber1(val any) (any, error) {
+	return val, nil
+}
+
+func (p *parser) callonNextNumber1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNextNumber1(stack["val"])
+}
+
+func (c *current) onFlag1() (any, error) {
+	return c.text[0] == '1', nil
+}
+
+func (p *parser) callonFlag1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFlag1()
 }
 
 func (c *current) onCoord1(x, y any) (any, error) {
-	return Coord{x.(string), y.(string)}, nil
+	return Coord{x.(float64), y.(float64)}, nil
 }
 
 func (p *parser) callonCoord1() (any, error) {
@@ -870,7 +1545,7 @@ func (p *parser) callonCoord1() (any, error) {
 }
 
 func (c *current) onNumber1(val any) (any, error) {
-	return string(c.text), nil
+	return strconv.ParseFloat(string(c.text), 64)
 }
 
 func (p *parser) callonNumber1() (any, error) {
@@ -909,24 +1584,34 @@ func (p *parser) calloncurve1() (any, error) {
 	return p.cur.oncurve1(stack["val"])
 }
 
-func (c *current) onmoveh1(val any) (any, error) {
+func (c *current) onscurve1(val any) (any, error) {
 	return isRelative(c.text)
 }
 
-func (p *parser) callonmoveh1() (any, error) {
+func (p *parser) callonscurve1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onmoveh1(stack["val"])
+	return p.cur.onscurve1(stack["val"])
 }
 
-func (c *current) onmovev1(val any) (any, error) {
+func (c *current) onhlineto1(val any) (any, error) {
 	return isRelative(c.text)
 }
 
-func (p *parser) callonmovev1() (any, error) {
+func (p *parser) callonhlineto1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onmovev1(stack["val"])
+	return p.cur.onhlineto1(stack["val"])
+}
+
+func (c *current) onvlineto1(val any) (any, error) {
+	return isRelative(c.text)
+}
+
+func (p *parser) callonvlineto1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onvlineto1(stack["val"])
 }
 
 func (c *current) onqcurve1(val any) (any, error) {
@@ -939,6 +1624,36 @@ func (p *parser) callonqcurve1() (any, error) {
 	return p.cur.onqcurve1(stack["val"])
 }
 
+func (c *current) onsqcurve1(val any) (any, error) {
+	return isRelative(c.text)
+}
+
+func (p *parser) callonsqcurve1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onsqcurve1(stack["val"])
+}
+
+func (c *current) onarc1(val any) (any, error) {
+	return isRelative(c.text)
+}
+
+func (p *parser) callonarc1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onarc1(stack["val"])
+}
+
+func (c *current) onRest1() (any, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonRest1() (any, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onRest1()
+}
+
 func (c *current) on_1() (any, error) {
 	return nil, nil
 }
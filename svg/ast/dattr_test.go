@@ -0,0 +1,53 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        string
+		want     CommandList
+		unparsed string
+	}{
+		{"absolute moveto and lineto", "M1 2 L3 4", CommandList{
+			&MoveTo{Coord: Coord{1, 2}}, &LineTo{Coord: Coord{3, 4}},
+		}, ""},
+		{"implicit linetos after a moveto", "m1,2 3,4", CommandList{
+			&MoveTo{Coord: Coord{1, 2}, Relative: true}, &LineTo{Coord: Coord{3, 4}, Relative: true},
+		}, ""},
+		{"numbers without separators", "M.5.5L10-20", CommandList{
+			&MoveTo{Coord: Coord{0.5, 0.5}}, &LineTo{Coord: Coord{10, -20}},
+		}, ""},
+		{"signs, trailing points and exponents", "M+3 5.L1e-3 -2E1", CommandList{
+			&MoveTo{Coord: Coord{3, 5}}, &LineTo{Coord: Coord{0.001, -20}},
+		}, ""},
+		{"closepath", "M0 0 h1 z", CommandList{
+			&MoveTo{Coord: Coord{0, 0}}, &HorizontalLineTo{X: 1, Relative: true}, &ClosePath{},
+		}, ""},
+		{"error after valid commands", "M0 0 L1 1 L2", CommandList{
+			&MoveTo{Coord: Coord{0, 0}}, &LineTo{Coord: Coord{1, 1}},
+		}, "L2"},
+		{"leftover argument", "M0 0 L1 1 2", CommandList{
+			&MoveTo{Coord: Coord{0, 0}}, &LineTo{Coord: Coord{1, 1}},
+		}, "2"},
+		{"nothing valid", "hello", CommandList{}, "hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := Parse(tt.name, []byte(tt.d))
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.d, err)
+			}
+			path := tree.(*Path)
+			if !reflect.DeepEqual(path.Children, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.d, path.Children, tt.want)
+			}
+			if path.Unparsed != tt.unparsed {
+				t.Errorf("Parse(%q) left %q unparsed, want %q", tt.d, path.Unparsed, tt.unparsed)
+			}
+		})
+	}
+}
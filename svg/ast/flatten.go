@@ -0,0 +1,50 @@
+package ast
+
+import "math"
+
+// maxFlattenDepth bounds the subdivision of a single cubic to 2^maxFlattenDepth pieces.
+const maxFlattenDepth = 16
+
+// FlattenCubic approximates the cubic bezier from start, with the given two control points and end point, by
+// straight lines. The curve is split in half until each piece deviates from its chord by at most tolerance.
+// The result holds the points after start, ending at the bezier's end point.
+func FlattenCubic(start Coord, segment Coords, tolerance float64) Coords {
+	points := Coords{}
+	flattenCubic(start, segment[0], segment[1], segment[2], tolerance, 0, &points)
+	return points
+}
+
+func flattenCubic(p0, p1, p2, p3 Coord, tolerance float64, depth int, points *Coords) {
+	if depth >= maxFlattenDepth || cubicFlatness(p0, p1, p2, p3) <= tolerance {
+		*points = append(*points, p3)
+		return
+	}
+	// De Casteljau subdivision at t = 0.5
+	p01, p12, p23 := midpoint(p0, p1), midpoint(p1, p2), midpoint(p2, p3)
+	p012, p123 := midpoint(p01, p12), midpoint(p12, p23)
+	mid := midpoint(p012, p123)
+	flattenCubic(p0, p01, p012, mid, tolerance, depth+1, points)
+	flattenCubic(mid, p123, p23, p3, tolerance, depth+1, points)
+}
+
+// cubicFlatness returns an upper bound of the distance between a cubic bezier and its chord: the curve lies
+// within the convex hull of its control points, so it's no further away than the farthest control point.
+func cubicFlatness(p0, p1, p2, p3 Coord) float64 {
+	return max(distanceToSegment(p1, p0, p3), distanceToSegment(p2, p0, p3))
+}
+
+// distanceToSegment returns the distance from p to the line segment from a to b.
+func distanceToSegment(p, a, b Coord) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	lengthSquared := dx*dx + dy*dy
+	if lengthSquared == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / lengthSquared
+	t = max(0, min(1, t))
+	return math.Hypot(p[0]-(a[0]+t*dx), p[1]-(a[1]+t*dy))
+}
+
+func midpoint(a, b Coord) Coord {
+	return Coord{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+}
@@ -0,0 +1,52 @@
+package ast
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestFlattenCubic(t *testing.T) {
+	tests := []struct {
+		name  string
+		start Coord
+		curve Coords
+	}{
+		{"gentle arch", Coord{0, 0}, Coords{{10, 10}, {20, 10}, {30, 0}}},
+		{"s-curve", Coord{0, 0}, Coords{{30, 40}, {-10, 40}, {20, 0}}},
+		{"loop", Coord{0, 0}, Coords{{40, 30}, {-20, 30}, {20, 0}}},
+		{"cusp", Coord{0, 0}, Coords{{30, 20}, {0, 20}, {30, 0}}},
+		{"control points beyond the ends", Coord{0, 0}, Coords{{-20, 5}, {50, 5}, {30, 0}}},
+		{"small", Coord{1, 1}, Coords{{1.1, 1.2}, {1.2, 1.2}, {1.3, 1}}},
+	}
+	for _, tt := range tests {
+		for _, tolerance := range []float64{1, 0.1, 0.01, 0.001} {
+			t.Run(fmt.Sprintf("%s within %v", tt.name, tolerance), func(t *testing.T) {
+				points := FlattenCubic(tt.start, tt.curve, tolerance)
+				if len(points) == 0 || points[len(points)-1] != tt.curve[2] {
+					t.Fatalf("points %v don't end at the curve's end point %v", points, tt.curve[2])
+				}
+				polyline := append(Coords{tt.start}, points...)
+				// Every point sampled along the curve must be within the tolerance of the polyline
+				for i := 0; i <= 1000; i++ {
+					p := cubicPoint(tt.start, tt.curve[0], tt.curve[1], tt.curve[2], float64(i)/1000)
+					distance := math.Inf(1)
+					for j := 0; j+1 < len(polyline); j++ {
+						distance = min(distance, distanceToSegment(p, polyline[j], polyline[j+1]))
+					}
+					if distance > tolerance*(1+1e-9) {
+						t.Fatalf("curve point %v is %v from the polyline, more than %v", p, distance, tolerance)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestFlattenCubicStraight(t *testing.T) {
+	// A cubic whose control points are on the line between its ends needs no subdivision
+	points := FlattenCubic(Coord{0, 0}, Coords{{10, 10}, {20, 20}, {30, 30}}, 0.01)
+	if len(points) != 1 || points[0] != (Coord{30, 30}) {
+		t.Errorf("got %v, want only the end point", points)
+	}
+}
@@ -0,0 +1,339 @@
+package ast
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// Winding returns the winding number of the closed polygon c around p: the number of times it goes around p
+// counter-clockwise, in a y-up coordinate system, minus the number of times it goes around clockwise.
+func (c Coords) Winding(p Coord) int {
+	winding := 0
+	for i := range c {
+		winding += edge{c[i], c[(i+1)%len(c)]}.winding(p)
+	}
+	return winding
+}
+
+// EvenOddBoundaries returns the polygons that bound the filled area of a shape made of several polygons, so
+// that filling them with the even-odd rule gives the same shape. With the even-odd rule that is all of them.
+// With the nonzero rule, polygons that don't meet any other, or themselves, are kept or dropped whole, depending
+// on whether they separate filled from unfilled area. The rest are split wherever they cross or overlap, and only
+// the pieces with the filled area on one side and not the other are kept, joined up into new polygons. This works
+// for any arrangement, e.g. partly overlapping subpaths, or a self-intersecting star.
+func EvenOddBoundaries(polygons []Coords, nonzero bool) []Coords {
+	if !nonzero {
+		return polygons
+	}
+	bounds := EmptyBounds()
+	for _, poly := range polygons {
+		for _, p := range poly {
+			bounds = bounds.Add(p)
+		}
+	}
+	if bounds.IsEmpty() {
+		return polygons
+	}
+	size := bounds.Size()
+	scale := math.Max(math.Max(size[0], size[1]), 1e-300)
+
+	edges := []polygonEdge{}
+	for i, poly := range polygons {
+		for j := range poly {
+			if e := (edge{poly[j], poly[(j+1)%len(poly)]}); e.a != e.b {
+				edges = append(edges, polygonEdge{edge: e, polygon: i})
+			}
+		}
+	}
+	index := newWindingIndex(edges, bounds)
+	splits, touched := findIntersections(edges, scale)
+
+	// The side of e that the filled area is on, if it is only on one side of it
+	const (
+		neither = iota
+		left
+		right
+	)
+	side := func(e edge) int {
+		d := Coord{e.b[0] - e.a[0], e.b[1] - e.a[1]}
+		offset := scale * 1e-9 / math.Hypot(d[0], d[1])
+		mid := Coord{(e.a[0] + e.b[0]) / 2, (e.a[1] + e.b[1]) / 2}
+		l := index.winding(Coord{mid[0] - d[1]*offset, mid[1] + d[0]*offset}) != 0
+		r := index.winding(Coord{mid[0] + d[1]*offset, mid[1] - d[0]*offset}) != 0
+		switch {
+		case l && !r:
+			return left
+		case r && !l:
+			return right
+		}
+		return neither
+	}
+
+	result := []Coords{}
+	for i, poly := range polygons {
+		if touched[i] {
+			continue
+		}
+		// Nothing crosses the polygon, so the filled area is on the same side of every edge, and the longest
+		// edge tells which one most reliably
+		longest, length := edge{}, -1.0
+		for j := range poly {
+			e := edge{poly[j], poly[(j+1)%len(poly)]}
+			if l := math.Hypot(e.b[0]-e.a[0], e.b[1]-e.a[1]); l > length {
+				longest, length = e, l
+			}
+		}
+		if length <= 0 {
+			continue
+		}
+		switch side(longest) {
+		case left:
+			result = append(result, poly)
+		case right:
+			reversed := slices.Clone(poly)
+			slices.Reverse(reversed)
+			result = append(result, reversed)
+		}
+	}
+
+	// Keep the pieces of the other polygons that separate filled from unfilled area, turned so that the filled
+	// side is on the left
+	boundary := []edge{}
+	for _, piece := range splitEdges(edges, splits, touched) {
+		switch side(piece) {
+		case left:
+			boundary = append(boundary, piece)
+		case right:
+			boundary = append(boundary, edge{piece.b, piece.a})
+		}
+	}
+	return append(result, joinEdges(boundary)...)
+}
+
+// edge is a straight line from a to b.
+type edge struct {
+	a, b Coord
+}
+
+// winding returns how much the edge adds to the winding number of a polygon it is part of around p. That is 1
+// if it crosses the horizontal line through p going up, to the right of p, -1 if it does so going down, and
+// otherwise 0.
+func (e edge) winding(p Coord) int {
+	// Which side of the edge the point is on
+	side := (e.b[0]-e.a[0])*(p[1]-e.a[1]) - (p[0]-e.a[0])*(e.b[1]-e.a[1])
+	if e.a[1] <= p[1] {
+		if e.b[1] > p[1] && side > 0 {
+			return 1
+		}
+	} else if e.b[1] <= p[1] && side < 0 {
+		return -1
+	}
+	return 0
+}
+
+// polygonEdge is an edge of one of the polygons passed to EvenOddBoundaries.
+type polygonEdge struct {
+	edge
+	polygon int // the index of the polygon
+}
+
+// windingIndex finds the winding number of a set of polygons around a point without going through all their
+// edges. Only the edges whose y range includes the point's y coordinate count, so the edges are put in buckets
+// by y range, and only those in the point's bucket are checked.
+type windingIndex struct {
+	minY, height float64
+	buckets      [][]edge
+}
+
+func newWindingIndex(edges []polygonEdge, bounds Bounds) *windingIndex {
+	index := &windingIndex{minY: bounds.Min[1], height: bounds.Size()[1], buckets: make([][]edge, max(1, len(edges)/4))}
+	for _, e := range edges {
+		for i := index.bucket(min(e.a[1], e.b[1])); i <= index.bucket(max(e.a[1], e.b[1])); i++ {
+			index.buckets[i] = append(index.buckets[i], e.edge)
+		}
+	}
+	return index
+}
+
+// bucket returns the index of the bucket that holds the edges whose y range includes y.
+func (index *windingIndex) bucket(y float64) int {
+	if index.height <= 0 {
+		return 0
+	}
+	i := int((y - index.minY) / index.height * float64(len(index.buckets)))
+	return min(max(i, 0), len(index.buckets)-1)
+}
+
+// winding returns the winding number of all the polygons together around p.
+func (index *windingIndex) winding(p Coord) int {
+	winding := 0
+	for _, e := range index.buckets[index.bucket(p[1])] {
+		winding += e.winding(p)
+	}
+	return winding
+}
+
+// findIntersections returns the points where each edge meets any other, and which polygons meet another polygon
+// or themselves anywhere but where consecutive edges join. Only pairs of edges whose bounding boxes overlap are
+// tested, which are found by sweeping across the edges from left to right.
+func findIntersections(edges []polygonEdge, scale float64) ([][]Coord, map[int]bool) {
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	minX := func(i int) float64 { return min(edges[i].a[0], edges[i].b[0]) }
+	sort.Slice(order, func(i, j int) bool { return minX(order[i]) < minX(order[j]) })
+
+	splits := make([][]Coord, len(edges))
+	touched := map[int]bool{}
+	for k, i := range order {
+		e := edges[i]
+		maxX := max(e.a[0], e.b[0])
+		minY, maxY := min(e.a[1], e.b[1]), max(e.a[1], e.b[1])
+		for _, j := range order[k+1:] {
+			f := edges[j]
+			if minX(j) > maxX {
+				break
+			}
+			if max(f.a[1], f.b[1]) < minY || min(f.a[1], f.b[1]) > maxY {
+				continue
+			}
+			for _, s := range intersections(e.edge, f.edge, scale) {
+				splits[i] = append(splits[i], s)
+				splits[j] = append(splits[j], s)
+				consecutive := e.polygon == f.polygon && (s == e.a && s == f.b || s == e.b && s == f.a)
+				if !consecutive {
+					touched[e.polygon], touched[f.polygon] = true, true
+				}
+			}
+		}
+	}
+	return splits, touched
+}
+
+// splitEdges returns the edges of the touched polygons, split at the points where they meet another edge, so
+// that edges only meet at their ends. Edges that overlap are split into identical pieces, which appear only once.
+func splitEdges(edges []polygonEdge, splits [][]Coord, touched map[int]bool) []edge {
+	pieces := []edge{}
+	seen := map[edge]bool{}
+	for i, e := range edges {
+		if !touched[e.polygon] {
+			continue
+		}
+		d := Coord{e.b[0] - e.a[0], e.b[1] - e.a[1]}
+		along := func(p Coord) float64 { return (p[0]-e.a[0])*d[0] + (p[1]-e.a[1])*d[1] }
+		points := append([]Coord{e.a, e.b}, splits[i]...)
+		sort.SliceStable(points, func(i, j int) bool { return along(points[i]) < along(points[j]) })
+		for k := 0; k+1 < len(points); k++ {
+			piece := edge{points[k], points[k+1]}
+			if piece.a == piece.b || seen[piece] || seen[edge{piece.b, piece.a}] {
+				continue
+			}
+			seen[piece] = true
+			pieces = append(pieces, piece)
+		}
+	}
+	return pieces
+}
+
+// intersections returns the points where two edges meet, other than at ends they share. When the edges overlap,
+// these are the ends of each that lie on the other.
+func intersections(e, f edge, scale float64) []Coord {
+	r := Coord{e.b[0] - e.a[0], e.b[1] - e.a[1]}
+	s := Coord{f.b[0] - f.a[0], f.b[1] - f.a[1]}
+	qp := Coord{f.a[0] - e.a[0], f.a[1] - e.a[1]}
+	cross := func(u, v Coord) float64 { return u[0]*v[1] - u[1]*v[0] }
+	denom := cross(r, s)
+	eps := 1e-12
+
+	if math.Abs(denom) <= eps*math.Hypot(r[0], r[1])*math.Hypot(s[0], s[1]) {
+		// Parallel, so they only meet if they are on the same line
+		if math.Abs(cross(qp, r)) > 1e-9*scale*math.Hypot(r[0], r[1]) {
+			return nil
+		}
+		points := []Coord{}
+		for _, p := range []Coord{f.a, f.b} {
+			if t := param(e, p); t > eps && t < 1-eps {
+				points = append(points, p)
+			}
+		}
+		for _, p := range []Coord{e.a, e.b} {
+			if u := param(f, p); u > eps && u < 1-eps {
+				points = append(points, p)
+			}
+		}
+		return points
+	}
+	t, u := cross(qp, s)/denom, cross(qp, r)/denom
+	if t < -eps || t > 1+eps || u < -eps || u > 1+eps {
+		return nil
+	}
+	// Use the exact end point where the edges meet at one, so the pieces join up
+	switch {
+	case t <= eps:
+		return []Coord{e.a}
+	case t >= 1-eps:
+		return []Coord{e.b}
+	case u <= eps:
+		return []Coord{f.a}
+	case u >= 1-eps:
+		return []Coord{f.b}
+	}
+	return []Coord{snap(Coord{e.a[0] + t*r[0], e.a[1] + t*r[1]}, scale)}
+}
+
+// param returns how far along the edge the projection of p is, from 0 at a to 1 at b.
+func param(e edge, p Coord) float64 {
+	d := Coord{e.b[0] - e.a[0], e.b[1] - e.a[1]}
+	return ((p[0]-e.a[0])*d[0] + (p[1]-e.a[1])*d[1]) / (d[0]*d[0] + d[1]*d[1])
+}
+
+// snap rounds a crossing point to a fine grid, so that where several edges cross at one point, the points
+// computed separately for each pair of them match.
+func snap(p Coord, scale float64) Coord {
+	grid := scale * 1e-9
+	return p.Map(func(x, y float64) (float64, float64) { return math.Round(x/grid) * grid, math.Round(y/grid) * grid })
+}
+
+// joinEdges joins edges end to start into closed polygons. Every point must have as many edges starting at it
+// as ending at it.
+func joinEdges(edges []edge) []Coords {
+	from := map[Coord][]int{}
+	for i, e := range edges {
+		from[e.a] = append(from[e.a], i)
+	}
+	used := make([]bool, len(edges))
+	next := func(p Coord) (int, bool) {
+		for len(from[p]) > 0 {
+			i := from[p][0]
+			from[p] = from[p][1:]
+			if !used[i] {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+	polygons := []Coords{}
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		used[start] = true
+		poly := Coords{edges[start].a}
+		p := edges[start].b
+		for p != edges[start].a {
+			i, ok := next(p)
+			if !ok {
+				break // the polygon can't be closed because of rounding, so it ends here
+			}
+			used[i] = true
+			poly = append(poly, p)
+			p = edges[i].b
+		}
+		if len(poly) >= 3 {
+			polygons = append(polygons, poly)
+		}
+	}
+	return polygons
+}
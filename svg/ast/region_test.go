@@ -0,0 +1,92 @@
+package ast
+
+import (
+	"math"
+	"testing"
+)
+
+func square(x, y, size float64, clockwise bool) Coords {
+	c := Coords{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}}
+	if clockwise {
+		c = Coords{c[0], c[3], c[2], c[1]}
+	}
+	return c
+}
+
+// circle returns a polygon with n points around a circle.
+func circle(x, y, r float64, n int) Coords {
+	c := make(Coords, n)
+	for i := range c {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		c[i] = Coord{x + r*math.Cos(a), y + r*math.Sin(a)}
+	}
+	return c
+}
+
+func TestEvenOddBoundaries(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons []Coords
+	}{
+		{"single square", []Coords{square(0, 0, 10, false)}},
+		{"partly overlapping squares", []Coords{square(0, 0, 20, false), square(10, 10, 20, false)}},
+		{"overlapping squares running opposite ways", []Coords{square(0, 0, 20, false), square(10, 10, 20, true)}},
+		{"hole running the other way", []Coords{square(0, 0, 20, false), square(5, 5, 10, true)}},
+		{"nested square running the same way", []Coords{square(0, 0, 20, false), square(5, 5, 10, false)}},
+		{"squares sharing an edge", []Coords{square(0, 0, 10, false), square(10, 0, 10, false)}},
+		{"separate squares running opposite ways", []Coords{square(0, 0, 10, false), square(20, 20, 10, true)}},
+		{"square in a hole in a square",
+			[]Coords{square(0, 0, 30, true), square(5, 5, 20, false), square(10, 10, 10, true)}},
+		{"separate square beside overlapping ones",
+			[]Coords{square(0, 0, 10, false), square(5, 5, 10, false), square(25, 25, 10, true)}},
+		{"overlapping circles with many points", []Coords{circle(15, 20, 12, 2000), circle(25, 20, 12, 2000)}},
+		{"self-intersecting star", []Coords{{{20, 0}, {32, 36}, {1, 14}, {39, 14}, {8, 36}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EvenOddBoundaries(tt.polygons, true)
+			// Sample a grid of points that never lands on an edge, and check that filling the result with the
+			// even-odd rule matches filling the input with the nonzero rule
+			for x := -1.03; x < 41; x += 0.5 {
+				for y := -1.07; y < 41; y += 0.5 {
+					p := Coord{x, y}
+					nonzero, evenOdd := 0, 0
+					for _, poly := range tt.polygons {
+						nonzero += poly.Winding(p)
+					}
+					for _, poly := range result {
+						evenOdd += poly.Winding(p)
+					}
+					if want, got := nonzero != 0, evenOdd%2 != 0; want != got {
+						t.Fatalf("at %v: filled = %t, want %t, boundaries %v", p, got, want, result)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEvenOddBoundariesKeepsEvenOdd(t *testing.T) {
+	polygons := []Coords{square(0, 0, 20, false), square(5, 5, 10, false)}
+	if result := EvenOddBoundaries(polygons, false); len(result) != 2 {
+		t.Errorf("got %d polygons, want the 2 given", len(result))
+	}
+}
+
+func TestWinding(t *testing.T) {
+	tests := []struct {
+		poly Coords
+		p    Coord
+		want int
+	}{
+		{square(0, 0, 10, false), Coord{5, 5}, 1},
+		{square(0, 0, 10, true), Coord{5, 5}, -1},
+		{square(0, 0, 10, false), Coord{15, 5}, 0},
+		{square(0, 0, 10, false), Coord{5, math.Inf(1)}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.poly.Winding(tt.p); got != tt.want {
+			t.Errorf("Winding(%v) of %v = %d, want %d", tt.p, tt.poly, got, tt.want)
+		}
+	}
+}
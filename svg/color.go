@@ -0,0 +1,195 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mattolenik/svg2scad/svg/ast"
+)
+
+var colorFuncRegex = regexp.MustCompile(`^(rgba?|hsla?)\(\s*(.*?)\s*\)$`)
+
+// ParseColor parses a CSS color: #rgb, #rgba, #rrggbb, #rrggbbaa, rgb(), rgba(), hsl(), hsla() or a named
+// color. currentColor depends on the element, so it is handled by the caller.
+func ParseColor(s string) (ast.Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if strings.HasPrefix(s, "#") {
+		return parseHexColor(s)
+	}
+	if match := colorFuncRegex.FindStringSubmatch(s); match != nil {
+		return parseColorFunc(match[1], match[2])
+	}
+	if c, ok := namedColors[s]; ok {
+		return c, nil
+	}
+	return ast.Color{}, fmt.Errorf("invalid color %q", s)
+}
+
+func parseHexColor(s string) (ast.Color, error) {
+	hex := s[1:]
+	if len(hex) == 3 || len(hex) == 4 {
+		// Each digit is doubled, e.g. #f80 is #ff8800
+		long := ""
+		for _, digit := range hex {
+			long += strings.Repeat(string(digit), 2)
+		}
+		hex = long
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil {
+		return ast.Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	return ast.Color{R: int(v >> 24), G: int(v >> 16 & 0xff), B: int(v >> 8 & 0xff), A: int(v & 0xff)}, nil
+}
+
+// parseColorFunc parses the arguments of rgb(), rgba(), hsl() or hsla(), in either the legacy comma separated
+// syntax, e.g. "255, 0, 0, 0.5", or the space separated one, e.g. "255 0 0 / 50%".
+func parseColorFunc(name, args string) (ast.Color, error) {
+	invalid := fmt.Errorf("invalid color %s(%s)", name, args)
+	var fields []string
+	if strings.Contains(args, ",") {
+		fields = strings.Split(args, ",")
+	} else {
+		main, alpha, hasAlpha := strings.Cut(args, "/")
+		fields = strings.Fields(main)
+		if hasAlpha {
+			fields = append(fields, alpha)
+		}
+	}
+	if len(fields) != 3 && len(fields) != 4 {
+		return ast.Color{}, invalid
+	}
+	alpha := 1.0
+	if len(fields) == 4 {
+		a, err := parseFraction(fields[3], 1)
+		if err != nil {
+			return ast.Color{}, invalid
+		}
+		alpha = a
+	}
+	var r, g, b float64
+	if strings.HasPrefix(name, "rgb") {
+		rgb := [3]float64{}
+		for i := range rgb {
+			v, err := parseFraction(fields[i], 255)
+			if err != nil {
+				return ast.Color{}, invalid
+			}
+			rgb[i] = v
+		}
+		r, g, b = rgb[0], rgb[1], rgb[2]
+	} else {
+		h, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(fields[0]), "deg"), 64)
+		if err != nil {
+			return ast.Color{}, invalid
+		}
+		s, errS := parsePercent(fields[1])
+		l, errL := parsePercent(fields[2])
+		if errS != nil || errL != nil {
+			return ast.Color{}, invalid
+		}
+		r, g, b = hslToRGB(h, s, l)
+	}
+	return ast.Color{R: channel(r), G: channel(g), B: channel(b), A: channel(alpha)}, nil
+}
+
+// parseFraction parses a number or a percentage into a fraction of 1, where a plain number is a fraction of max.
+func parseFraction(s string, max float64) (float64, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		return parsePercent(s)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return clamp(v / max), nil
+}
+
+func parsePercent(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasSuffix(s, "%") {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, err
+	}
+	return clamp(v / 100), nil
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// channel converts a fraction of 1 to a color channel from 0 to 255.
+func channel(v float64) int {
+	return int(math.Round(clamp(v) * 255))
+}
+
+// hslToRGB converts a hue in degrees, and a saturation and lightness from 0 to 1, to red, green and blue from 0 to 1.
+// This is the algorithm given in CSS Color 4.
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	h = math.Mod(math.Mod(h, 360)+360, 360)
+	a := s * math.Min(l, 1-l)
+	f := func(n float64) float64 {
+		k := math.Mod(n+h/30, 12)
+		return l - a*math.Max(-1, math.Min(math.Min(k-3, 9-k), 1))
+	}
+	return f(0), f(8), f(4)
+}
+
+// namedColors are the CSS named colors, including transparent.
+var namedColors = newNamedColors()
+
+func newNamedColors() map[string]ast.Color {
+	colors := map[string]ast.Color{"transparent": {R: 0, G: 0, B: 0, A: 0}}
+	for name, rgb := range map[string]uint32{
+		"aliceblue": 0xf0f8ff, "antiquewhite": 0xfaebd7, "aqua": 0x00ffff, "aquamarine": 0x7fffd4,
+		"azure": 0xf0ffff, "beige": 0xf5f5dc, "bisque": 0xffe4c4, "black": 0x000000,
+		"blanchedalmond": 0xffebcd, "blue": 0x0000ff, "blueviolet": 0x8a2be2, "brown": 0xa52a2a,
+		"burlywood": 0xdeb887, "cadetblue": 0x5f9ea0, "chartreuse": 0x7fff00, "chocolate": 0xd2691e,
+		"coral": 0xff7f50, "cornflowerblue": 0x6495ed, "cornsilk": 0xfff8dc, "crimson": 0xdc143c,
+		"cyan": 0x00ffff, "darkblue": 0x00008b, "darkcyan": 0x008b8b, "darkgoldenrod": 0xb8860b,
+		"darkgray": 0xa9a9a9, "darkgreen": 0x006400, "darkgrey": 0xa9a9a9, "darkkhaki": 0xbdb76b,
+		"darkmagenta": 0x8b008b, "darkolivegreen": 0x556b2f, "darkorange": 0xff8c00, "darkorchid": 0x9932cc,
+		"darkred": 0x8b0000, "darksalmon": 0xe9967a, "darkseagreen": 0x8fbc8f, "darkslateblue": 0x483d8b,
+		"darkslategray": 0x2f4f4f, "darkslategrey": 0x2f4f4f, "darkturquoise": 0x00ced1, "darkviolet": 0x9400d3,
+		"deeppink": 0xff1493, "deepskyblue": 0x00bfff, "dimgray": 0x696969, "dimgrey": 0x696969,
+		"dodgerblue": 0x1e90ff, "firebrick": 0xb22222, "floralwhite": 0xfffaf0, "forestgreen": 0x228b22,
+		"fuchsia": 0xff00ff, "gainsboro": 0xdcdcdc, "ghostwhite": 0xf8f8ff, "gold": 0xffd700,
+		"goldenrod": 0xdaa520, "gray": 0x808080, "green": 0x008000, "greenyellow": 0xadff2f,
+		"grey": 0x808080, "honeydew": 0xf0fff0, "hotpink": 0xff69b4, "indianred": 0xcd5c5c,
+		"indigo": 0x4b0082, "ivory": 0xfffff0, "khaki": 0xf0e68c, "lavender": 0xe6e6fa,
+		"lavenderblush": 0xfff0f5, "lawngreen": 0x7cfc00, "lemonchiffon": 0xfffacd, "lightblue": 0xadd8e6,
+		"lightcoral": 0xf08080, "lightcyan": 0xe0ffff, "lightgoldenrodyellow": 0xfafad2, "lightgray": 0xd3d3d3,
+		"lightgreen": 0x90ee90, "lightgrey": 0xd3d3d3, "lightpink": 0xffb6c1, "lightsalmon": 0xffa07a,
+		"lightseagreen": 0x20b2aa, "lightskyblue": 0x87cefa, "lightslategray": 0x778899, "lightslategrey": 0x778899,
+		"lightsteelblue": 0xb0c4de, "lightyellow": 0xffffe0, "lime": 0x00ff00, "limegreen": 0x32cd32,
+		"linen": 0xfaf0e6, "magenta": 0xff00ff, "maroon": 0x800000, "mediumaquamarine": 0x66cdaa,
+		"mediumblue": 0x0000cd, "mediumorchid": 0xba55d3, "mediumpurple": 0x9370db, "mediumseagreen": 0x3cb371,
+		"mediumslateblue": 0x7b68ee, "mediumspringgreen": 0x00fa9a, "mediumturquoise": 0x48d1cc, "mediumvioletred": 0xc71585,
+		"midnightblue": 0x191970, "mintcream": 0xf5fffa, "mistyrose": 0xffe4e1, "moccasin": 0xffe4b5,
+		"navajowhite": 0xffdead, "navy": 0x000080, "oldlace": 0xfdf5e6, "olive": 0x808000,
+		"olivedrab": 0x6b8e23, "orange": 0xffa500, "orangered": 0xff4500, "orchid": 0xda70d6,
+		"palegoldenrod": 0xeee8aa, "palegreen": 0x98fb98, "paleturquoise": 0xafeeee, "palevioletred": 0xdb7093,
+		"papayawhip": 0xffefd5, "peachpuff": 0xffdab9, "peru": 0xcd853f, "pink": 0xffc0cb,
+		"plum": 0xdda0dd, "powderblue": 0xb0e0e6, "purple": 0x800080, "rebeccapurple": 0x663399,
+		"red": 0xff0000, "rosybrown": 0xbc8f8f, "royalblue": 0x4169e1, "saddlebrown": 0x8b4513,
+		"salmon": 0xfa8072, "sandybrown": 0xf4a460, "seagreen": 0x2e8b57, "seashell": 0xfff5ee,
+		"sienna": 0xa0522d, "silver": 0xc0c0c0, "skyblue": 0x87ceeb, "slateblue": 0x6a5acd,
+		"slategray": 0x708090, "slategrey": 0x708090, "snow": 0xfffafa, "springgreen": 0x00ff7f,
+		"steelblue": 0x4682b4, "tan": 0xd2b48c, "teal": 0x008080, "thistle": 0xd8bfd8,
+		"tomato": 0xff6347, "turquoise": 0x40e0d0, "violet": 0xee82ee, "wheat": 0xf5deb3,
+		"white": 0xffffff, "whitesmoke": 0xf5f5f5, "yellow": 0xffff00, "yellowgreen": 0x9acd32,
+	} {
+		colors[name] = ast.Color{R: int(rgb >> 16), G: int(rgb >> 8 & 0xff), B: int(rgb & 0xff), A: 255}
+	}
+	return colors
+}
@@ -0,0 +1,64 @@
+package svg
+
+import (
+	"testing"
+
+	"github.com/mattolenik/svg2scad/svg/ast"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		color string
+		want  ast.Color
+	}{
+		{"#f80", ast.Color{R: 255, G: 136, B: 0, A: 255}},
+		{"#f808", ast.Color{R: 255, G: 136, B: 0, A: 136}},
+		{"#ff8000", ast.Color{R: 255, G: 128, B: 0, A: 255}},
+		{"#FF800080", ast.Color{R: 255, G: 128, B: 0, A: 128}},
+		{"  #ff8000 ", ast.Color{R: 255, G: 128, B: 0, A: 255}},
+		{"rgb(255, 128, 0)", ast.Color{R: 255, G: 128, B: 0, A: 255}},
+		{"rgb(100%, 50%, 0%)", ast.Color{R: 255, G: 128, B: 0, A: 255}},
+		{"rgba(255, 128, 0, 0.5)", ast.Color{R: 255, G: 128, B: 0, A: 128}},
+		{"rgb(255 128 0 / 50%)", ast.Color{R: 255, G: 128, B: 0, A: 128}},
+		{"rgb(300, -10, 0)", ast.Color{R: 255, G: 0, B: 0, A: 255}},
+		{"hsl(120, 100%, 50%)", ast.Color{R: 0, G: 255, B: 0, A: 255}},
+		{"hsl(240deg 100% 25%)", ast.Color{R: 0, G: 0, B: 128, A: 255}},
+		{"hsla(-120, 100%, 50%, 0.25)", ast.Color{R: 0, G: 0, B: 255, A: 64}},
+		{"hsl(0, 0%, 100%)", ast.Color{R: 255, G: 255, B: 255, A: 255}},
+		{"black", ast.Color{R: 0, G: 0, B: 0, A: 255}},
+		{"RebeccaPurple", ast.Color{R: 102, G: 51, B: 153, A: 255}},
+		{"transparent", ast.Color{R: 0, G: 0, B: 0, A: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			got, err := ParseColor(tt.color)
+			if err != nil {
+				t.Fatalf("ParseColor(%q) failed: %v", tt.color, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColor(%q) = %v, want %v", tt.color, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseColorErrors(t *testing.T) {
+	for _, color := range []string{
+		"",
+		"#ff",
+		"#ggg",
+		"#ff80001",
+		"rgb(1, 2)",
+		"rgb(1, 2, 3, 4, 5)",
+		"rgb(a, b, c)",
+		"hsl(120, 100, 50)",
+		"notacolor",
+		"currentColor",
+	} {
+		t.Run(color, func(t *testing.T) {
+			if c, err := ParseColor(color); err == nil {
+				t.Errorf("ParseColor(%q) = %v, want an error", color, c)
+			}
+		})
+	}
+}
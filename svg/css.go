@@ -0,0 +1,194 @@
+package svg
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mattolenik/svg2scad/log"
+)
+
+// Stylesheet holds the rules of every <style> element in a document, in document order.
+type Stylesheet []rule
+
+// rule is a CSS rule with a single selector. Rules with a list of selectors, e.g. "g, .a { ... }", are split
+// into one rule per selector, since each has its own specificity.
+type rule struct {
+	selector    selector
+	specificity [3]int // the number of IDs, classes and types in the selector
+	order       int    // position in the stylesheet, later rules win over earlier ones of the same specificity
+	normal      Declarations
+	important   Declarations // declared with !important
+}
+
+// selector is a list of compound selectors, each matching an ancestor of the element the next one matches.
+// Only the descendant combinator is supported.
+type selector []compound
+
+// compound is a simple selector such as "path", "#logo", ".cls-1" or "g.layer.hidden".
+type compound struct {
+	tag     string // empty or "*" matches any element
+	id      string
+	classes []string
+}
+
+var commentRegex = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+var compoundRegex = regexp.MustCompile(`^([A-Za-z][\w-]*|\*)?((?:[.#][\w-]+)*)$`)
+
+var simpleSelectorRegex = regexp.MustCompile(`[.#][\w-]+`)
+
+// ParseStylesheet parses the contents of a <style> element. At-rules such as @media and @font-face, and rules
+// with selectors that aren't supported, are skipped.
+func ParseStylesheet(css string) Stylesheet {
+	sheet := Stylesheet{}
+	css = commentRegex.ReplaceAllString(css, "")
+	for {
+		open := strings.Index(css, "{")
+		if open < 0 {
+			return sheet
+		}
+		close := blockClose(css, open)
+		prelude, block := strings.TrimSpace(css[:open]), css[open+1:close]
+		css = css[min(close+1, len(css)):]
+		if strings.HasPrefix(prelude, "@") {
+			log.Debugf("skipping CSS at-rule %s", prelude)
+			continue
+		}
+		normal, important := ParseDeclarations(block)
+		for _, text := range strings.Split(prelude, ",") {
+			sel, ok := parseSelector(text)
+			if !ok {
+				log.Warnf("skipping unsupported CSS selector %q", strings.TrimSpace(text))
+				continue
+			}
+			sheet = append(sheet, rule{
+				selector:    sel,
+				specificity: sel.specificity(),
+				order:       len(sheet),
+				normal:      normal,
+				important:   important,
+			})
+		}
+	}
+}
+
+// blockClose returns the index of the brace that closes the block opened at open, or the length of css if the
+// block isn't closed.
+func blockClose(css string, open int) int {
+	depth := 0
+	for i := open; i < len(css); i++ {
+		switch css[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(css)
+}
+
+func parseSelector(text string) (selector, bool) {
+	sel := selector{}
+	for _, part := range strings.Fields(text) {
+		match := compoundRegex.FindStringSubmatch(part)
+		if match == nil {
+			return nil, false
+		}
+		c := compound{tag: match[1]}
+		for _, simple := range simpleSelectorRegex.FindAllString(match[2], -1) {
+			if simple[0] == '#' {
+				c.id = simple[1:]
+			} else {
+				c.classes = append(c.classes, simple[1:])
+			}
+		}
+		sel = append(sel, c)
+	}
+	return sel, len(sel) > 0
+}
+
+func (s selector) specificity() [3]int {
+	spec := [3]int{}
+	for _, c := range s {
+		if c.id != "" {
+			spec[0]++
+		}
+		spec[1] += len(c.classes)
+		if c.tag != "" && c.tag != "*" {
+			spec[2]++
+		}
+	}
+	return spec
+}
+
+// target is an element that selectors are matched against, along with its tag name.
+type target struct {
+	tag     string
+	element *Element
+}
+
+func (c compound) matches(t target) bool {
+	if c.tag != "" && c.tag != "*" && c.tag != t.tag {
+		return false
+	}
+	if c.id != "" && c.id != t.element.ID {
+		return false
+	}
+	classes := strings.Fields(t.element.Class)
+	for _, class := range c.classes {
+		found := false
+		for _, cl := range classes {
+			found = found || cl == class
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the selector matches the last element of path, which holds the element's ancestors
+// from the root down, followed by the element itself.
+func (s selector) matches(path []target) bool {
+	last := len(path) - 1
+	if !s[len(s)-1].matches(path[last]) {
+		return false
+	}
+	// Each remaining compound must match an ancestor, and it's always safe to take the nearest one
+	i := last - 1
+	for j := len(s) - 2; j >= 0; j-- {
+		for i >= 0 && !s[j].matches(path[i]) {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		i--
+	}
+	return true
+}
+
+// matching returns the rules that match the last element of path, in cascade order: lowest specificity first,
+// and in stylesheet order when the specificities are the same.
+func (sheet Stylesheet) matching(path []target) []rule {
+	rules := []rule{}
+	for _, r := range sheet {
+		if r.selector.matches(path) {
+			rules = append(rules, r)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i].specificity, rules[j].specificity
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return rules[i].order < rules[j].order
+	})
+	return rules
+}
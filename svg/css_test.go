@@ -0,0 +1,171 @@
+package svg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStylesheet(t *testing.T) {
+	type parsed struct {
+		specificity [3]int
+		normal      Declarations
+		important   Declarations
+	}
+	tests := []struct {
+		name string
+		css  string
+		want []parsed
+	}{
+		{"type selector", "path { fill: red }", []parsed{
+			{[3]int{0, 0, 1}, Declarations{"fill": "red"}, Declarations{}},
+		}},
+		{"compound selector", "g.layer.top#logo { fill: red; stroke: blue }", []parsed{
+			{[3]int{1, 2, 1}, Declarations{"fill": "red", "stroke": "blue"}, Declarations{}},
+		}},
+		{"descendant selector", "#logo .cls-1 path { fill: red }", []parsed{
+			{[3]int{1, 1, 1}, Declarations{"fill": "red"}, Declarations{}},
+		}},
+		{"universal selector", "* { fill: red }", []parsed{
+			{[3]int{0, 0, 0}, Declarations{"fill": "red"}, Declarations{}},
+		}},
+		{"selector list", ".a, path { fill: red }", []parsed{
+			{[3]int{0, 1, 0}, Declarations{"fill": "red"}, Declarations{}},
+			{[3]int{0, 0, 1}, Declarations{"fill": "red"}, Declarations{}},
+		}},
+		{"important", ".a { fill: red !important; stroke: blue }", []parsed{
+			{[3]int{0, 1, 0}, Declarations{"stroke": "blue"}, Declarations{"fill": "red"}},
+		}},
+		{"comments and several rules", "/* { */ .a { fill: red } /* .b { fill: blue } */ .c { fill: green }", []parsed{
+			{[3]int{0, 1, 0}, Declarations{"fill": "red"}, Declarations{}},
+			{[3]int{0, 1, 0}, Declarations{"fill": "green"}, Declarations{}},
+		}},
+		{"at-rules", "@media print { .a { fill: red } } @font-face { font-family: x } .b { fill: blue }", []parsed{
+			{[3]int{0, 1, 0}, Declarations{"fill": "blue"}, Declarations{}},
+		}},
+		{"unsupported selectors", "g > path, a:hover, [id=x] { fill: red } .b { fill: blue }", []parsed{
+			{[3]int{0, 1, 0}, Declarations{"fill": "blue"}, Declarations{}},
+		}},
+		{"unclosed block", ".a { fill: red", []parsed{
+			{[3]int{0, 1, 0}, Declarations{"fill": "red"}, Declarations{}},
+		}},
+		{"empty", "  ", []parsed{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []parsed{}
+			for i, r := range ParseStylesheet(tt.css) {
+				if r.order != i {
+					t.Errorf("rule %d has order %d", i, r.order)
+				}
+				got = append(got, parsed{r.specificity, r.normal, r.important})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStylesheet(%q) = %+v, want %+v", tt.css, got, tt.want)
+			}
+		})
+	}
+}
+
+// testTree returns the targets from the root down to a path inside two groups:
+// <svg><g id="layer" class="a"><g class="b c"><path id="p" class="cls-1 cls-2"/></g></g></svg>
+func testTree() []target {
+	return []target{
+		{tag: "svg", element: &Element{}},
+		{tag: "g", element: &Element{ID: "layer", Class: "a"}},
+		{tag: "g", element: &Element{Class: "b c"}},
+		{tag: "path", element: &Element{ID: "p", Class: "cls-1 cls-2"}},
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"path", true},
+		{"*", true},
+		{"#p", true},
+		{".cls-1", true},
+		{".cls-1.cls-2", true},
+		{"path#p.cls-2", true},
+		{"g path", true},
+		{"svg path", true},
+		{"#layer path", true},
+		{".a .b.c path", true},
+		{"svg .a .b #p", true},
+		{"#layer g .cls-1", true},
+		{"g", false},
+		{"rect", false},
+		{"#q", false},
+		{".cls-3", false},
+		{".cls-1.cls-3", false},
+		{"rect path", false},
+		{".b .a path", false},
+		{"#layer #layer path", false},
+		{".c .c path", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, ok := parseSelector(tt.selector)
+			if !ok {
+				t.Fatalf("parseSelector(%q) failed", tt.selector)
+			}
+			if got := sel.matches(testTree()); got != tt.want {
+				t.Errorf("%q matches = %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStylesheetMatching(t *testing.T) {
+	sheet := ParseStylesheet(`
+		#p { fill: id }
+		path { fill: type }
+		.cls-1 { fill: class }
+		rect { fill: other }
+		g path { fill: descendant }
+		.cls-2 { fill: later-class }
+	`)
+	got := []string{}
+	for _, r := range sheet.matching(testTree()) {
+		got = append(got, r.normal["fill"])
+	}
+	// Lowest specificity first, then in stylesheet order
+	want := []string{"type", "descendant", "class", "later-class", "id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matching rules in order %v, want %v", got, want)
+	}
+}
+
+func TestDeclarationsCascade(t *testing.T) {
+	tests := []struct {
+		name         string
+		css          string
+		presentation string // the fill attribute
+		inline       string // the style attribute
+		want         string
+	}{
+		{"presentation attribute", "", "red", "", "red"},
+		{"stylesheet over presentation attribute", "path { fill: blue }", "red", "", "blue"},
+		{"higher specificity wins", "#p { fill: green } .cls-1 { fill: blue }", "", "", "green"},
+		{"later rule wins at the same specificity", ".cls-1 { fill: blue } .cls-2 { fill: green }", "", "", "green"},
+		{"inline style over stylesheet", "#p { fill: blue }", "red", "fill: yellow", "yellow"},
+		{"important over inline style", "path { fill: blue !important }", "", "fill: yellow", "blue"},
+		{"important over higher specificity", "path { fill: blue !important } #p { fill: green }", "", "", "blue"},
+		{"higher specificity among important", "#p { fill: green !important } path { fill: blue !important }", "", "", "green"},
+		{"inline important over stylesheet important", "#p { fill: green !important }", "", "fill: yellow !important", "yellow"},
+		{"last inline declaration wins", "", "", "fill: red; fill: blue", "blue"},
+		{"nothing specified", ".other { fill: blue }", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := testTree()
+			elem := path[len(path)-1].element
+			elem.Fill, elem.Style = tt.presentation, tt.inline
+			decls := elem.declarations(ParseStylesheet(tt.css), path)
+			if got := decls["fill"]; got != tt.want {
+				t.Errorf("fill is %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package svg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/mattolenik/svg2scad/log"
+	"github.com/mattolenik/svg2scad/svg/ast"
+)
+
+// Shape is a basic shape element, which is drawn the same way as an equivalent path.
+type Shape interface {
+	// ToPath converts the shape into a path, resolving percentage lengths against the viewport. It returns nil
+	// when the shape is not rendered, e.g. a zero-sized rect.
+	ToPath(vp Viewport) (*Path, error)
+}
+
+// kappa is the distance of the control points from the ends of a cubic bezier approximating a quarter of a
+// unit circle.
+const kappa = 0.5522847498307936
+
+type Rect struct {
+	Element
+	X      string `xml:"x,attr"`
+	Y      string `xml:"y,attr"`
+	Width  string `xml:"width,attr"`
+	Height string `xml:"height,attr"`
+	RX     string `xml:"rx,attr"`
+	RY     string `xml:"ry,attr"`
+}
+
+type Circle struct {
+	Element
+	CX string `xml:"cx,attr"`
+	CY string `xml:"cy,attr"`
+	R  string `xml:"r,attr"`
+}
+
+type Ellipse struct {
+	Element
+	CX string `xml:"cx,attr"`
+	CY string `xml:"cy,attr"`
+	RX string `xml:"rx,attr"`
+	RY string `xml:"ry,attr"`
+}
+
+type Line struct {
+	Element
+	X1 string `xml:"x1,attr"`
+	Y1 string `xml:"y1,attr"`
+	X2 string `xml:"x2,attr"`
+	Y2 string `xml:"y2,attr"`
+}
+
+type Polyline struct {
+	Element
+	Points string `xml:"points,attr"`
+}
+
+type Polygon struct {
+	Element
+	Points string `xml:"points,attr"`
+}
+
+// lengthAxes gives the axis of the viewport that percentages in each shape attribute are relative to.
+var lengthAxes = map[string]axis{
+	"x": horizontal, "cx": horizontal, "x1": horizontal, "x2": horizontal, "width": horizontal, "rx": horizontal,
+	"y": vertical, "cy": vertical, "y1": vertical, "y2": vertical, "height": vertical, "ry": vertical,
+	"r": diagonal,
+}
+
+// lengths parses a set of length attributes into user units. Missing attributes are zero.
+func lengths(name string, elem Element, vp Viewport, attrs map[string]string) (map[string]float64, error) {
+	result := map[string]float64{}
+	for attrName, attr := range attrs {
+		v, err := vp.parseUserUnits(attr, 0, lengthAxes[attrName])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s attribute on <%s> %q: %w", attrName, name, elem.ID, err)
+		}
+		result[attrName] = v
+	}
+	return result, nil
+}
+
+func newShapePath(name string, elem Element, cmds ...any) *Path {
+	return &Path{
+		XMLName: xml.Name{Local: name},
+		Element: elem,
+		tree:    &ast.Path{Children: ast.CommandList(cmds)},
+	}
+}
+
+func moveTo(x, y float64) *ast.MoveTo {
+	return &ast.MoveTo{Coord: ast.NewCoord(x, y)}
+}
+
+func lineTo(x, y float64) *ast.LineTo {
+	return &ast.LineTo{Coord: ast.NewCoord(x, y)}
+}
+
+func cubicTo(x1, y1, x2, y2, x, y float64) *ast.CubicBezier {
+	return &ast.CubicBezier{Points: ast.Coords{ast.NewCoord(x1, y1), ast.NewCoord(x2, y2), ast.NewCoord(x, y)}}
+}
+
+// ellipseArc returns a cubic bezier approximating the quarter of the ellipse centred on (cx, cy) that runs from
+// angle start (a multiple of 90 degrees, given in quarter turns) to the next quarter turn, in the positive
+// angle direction.
+func ellipseArc(cx, cy, rx, ry float64, start int) *ast.CubicBezier {
+	// Unit vectors for 0, 90, 180 and 270 degrees, y pointing down as in SVG
+	dirs := [4][2]float64{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+	from, to := dirs[start%4], dirs[(start+1)%4]
+	x0, y0 := cx+from[0]*rx, cy+from[1]*ry
+	x3, y3 := cx+to[0]*rx, cy+to[1]*ry
+	return cubicTo(
+		x0+to[0]*rx*kappa, y0+to[1]*ry*kappa,
+		x3+from[0]*rx*kappa, y3+from[1]*ry*kappa,
+		x3, y3)
+}
+
+// parseRadius parses rx or ry, which may be "auto" or missing.
+func parseRadius(name string, elem Element, attrName, attr string, vp Viewport) (float64, bool, error) {
+	if attr = strings.TrimSpace(attr); attr == "" || attr == "auto" {
+		return 0, false, nil
+	}
+	v, err := vp.parseUserUnits(attr, 0, lengthAxes[attrName])
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s attribute on <%s> %q: %w", attrName, name, elem.ID, err)
+	}
+	if v < 0 {
+		return 0, false, fmt.Errorf("negative %s attribute on <%s> %q", attrName, name, elem.ID)
+	}
+	return v, true, nil
+}
+
+// resolveRadii applies the rule shared by <rect> and <ellipse> that a missing or auto radius takes the value
+// of the other one.
+func resolveRadii(name string, elem Element, rxAttr, ryAttr string, vp Viewport) (float64, float64, error) {
+	rx, hasRX, err := parseRadius(name, elem, "rx", rxAttr, vp)
+	if err != nil {
+		return 0, 0, err
+	}
+	ry, hasRY, err := parseRadius(name, elem, "ry", ryAttr, vp)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !hasRX {
+		rx = ry
+	}
+	if !hasRY {
+		ry = rx
+	}
+	return rx, ry, nil
+}
+
+func (r *Rect) ToPath(vp Viewport) (*Path, error) {
+	l, err := lengths("rect", r.Element, vp, map[string]string{"x": r.X, "y": r.Y, "width": r.Width, "height": r.Height})
+	if err != nil {
+		return nil, err
+	}
+	x, y, w, h := l["x"], l["y"], l["width"], l["height"]
+	if w <= 0 || h <= 0 {
+		log.Infof("skipping <rect> %q, it has no area", r.ID)
+		return nil, nil
+	}
+	rx, ry, err := resolveRadii("rect", r.Element, r.RX, r.RY, vp)
+	if err != nil {
+		return nil, err
+	}
+	rx, ry = min(rx, w/2), min(ry, h/2)
+	if rx == 0 || ry == 0 {
+		return newShapePath("rect", r.Element,
+			moveTo(x, y), lineTo(x+w, y), lineTo(x+w, y+h), lineTo(x, y+h), &ast.ClosePath{}), nil
+	}
+	// Clockwise from the top edge, each straight edge followed by a rounded corner
+	return newShapePath("rect", r.Element,
+		moveTo(x+rx, y),
+		lineTo(x+w-rx, y), ellipseArc(x+w-rx, y+ry, rx, ry, 3),
+		lineTo(x+w, y+h-ry), ellipseArc(x+w-rx, y+h-ry, rx, ry, 0),
+		lineTo(x+rx, y+h), ellipseArc(x+rx, y+h-ry, rx, ry, 1),
+		lineTo(x, y+ry), ellipseArc(x+rx, y+ry, rx, ry, 2),
+		&ast.ClosePath{}), nil
+}
+
+func ellipsePath(name string, elem Element, cx, cy, rx, ry float64) *Path {
+	return newShapePath(name, elem,
+		moveTo(cx+rx, cy),
+		ellipseArc(cx, cy, rx, ry, 0),
+		ellipseArc(cx, cy, rx, ry, 1),
+		ellipseArc(cx, cy, rx, ry, 2),
+		ellipseArc(cx, cy, rx, ry, 3),
+		&ast.ClosePath{})
+}
+
+func (c *Circle) ToPath(vp Viewport) (*Path, error) {
+	l, err := lengths("circle", c.Element, vp, map[string]string{"cx": c.CX, "cy": c.CY, "r": c.R})
+	if err != nil {
+		return nil, err
+	}
+	if l["r"] <= 0 {
+		log.Infof("skipping <circle> %q, it has no area", c.ID)
+		return nil, nil
+	}
+	return ellipsePath("circle", c.Element, l["cx"], l["cy"], l["r"], l["r"]), nil
+}
+
+func (e *Ellipse) ToPath(vp Viewport) (*Path, error) {
+	l, err := lengths("ellipse", e.Element, vp, map[string]string{"cx": e.CX, "cy": e.CY})
+	if err != nil {
+		return nil, err
+	}
+	rx, ry, err := resolveRadii("ellipse", e.Element, e.RX, e.RY, vp)
+	if err != nil {
+		return nil, err
+	}
+	if rx == 0 || ry == 0 {
+		log.Infof("skipping <ellipse> %q, it has no area", e.ID)
+		return nil, nil
+	}
+	return ellipsePath("ellipse", e.Element, l["cx"], l["cy"], rx, ry), nil
+}
+
+// ToPath always returns nil, since a line encloses no area and so has nothing to fill.
+func (l *Line) ToPath(Viewport) (*Path, error) {
+	log.Infof("skipping <line> %q, it has no area", l.ID)
+	return nil, nil
+}
+
+// pointsPath converts the points attribute of a <polyline> or <polygon>.
+func pointsPath(name string, elem Element, points string, closed bool) (*Path, error) {
+	nums, err := parseNumberList(points)
+	if err != nil {
+		return nil, fmt.Errorf("invalid points attribute on <%s> %q: %w", name, elem.ID, err)
+	}
+	if len(nums)%2 != 0 {
+		// Per the spec, the shape is rendered up to the point in error
+		log.Infof("<%s> %q has an odd number of coordinates, ignoring the last one", name, elem.ID)
+		nums = nums[:len(nums)-1]
+	}
+	if len(nums) == 0 {
+		log.Infof("skipping <%s> %q, it has no points", name, elem.ID)
+		return nil, nil
+	}
+	cmds := []any{moveTo(nums[0], nums[1])}
+	for i := 2; i < len(nums); i += 2 {
+		cmds = append(cmds, lineTo(nums[i], nums[i+1]))
+	}
+	if closed {
+		cmds = append(cmds, &ast.ClosePath{})
+	}
+	return newShapePath(name, elem, cmds...), nil
+}
+
+func (p *Polyline) ToPath(Viewport) (*Path, error) {
+	return pointsPath("polyline", p.Element, p.Points, false)
+}
+
+func (p *Polygon) ToPath(Viewport) (*Path, error) {
+	return pointsPath("polygon", p.Element, p.Points, true)
+}
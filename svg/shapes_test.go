@@ -0,0 +1,96 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mattolenik/svg2scad/svg/ast"
+)
+
+// shapeSize returns the width and height in millimetres of the only path in the SVG, from the ends of its
+// commands. It is only meant for shapes made of straight lines.
+func shapeSize(t *testing.T, s *SVG, dpi float64) (float64, float64) {
+	t.Helper()
+	if len(s.Paths) != 1 {
+		t.Fatalf("got %d paths, want 1", len(s.Paths))
+	}
+	tree, err := s.Paths[0].Parse()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	viewport, err := s.ViewportTransform(dpi)
+	if err != nil {
+		t.Fatalf("ViewportTransform failed: %v", err)
+	}
+	bounds := ast.EmptyBounds()
+	for _, cmd := range tree.Children.(ast.CommandList) {
+		switch cmd := cmd.(type) {
+		case *ast.MoveTo:
+			bounds = bounds.Add(ast.NewCoord(viewport.Apply(cmd.Coord.XY())))
+		case *ast.LineTo:
+			bounds = bounds.Add(ast.NewCoord(viewport.Apply(cmd.Coord.XY())))
+		}
+	}
+	size := bounds.Size()
+	return size[0], size[1]
+}
+
+func TestShapeAbsoluteUnits(t *testing.T) {
+	tests := []struct {
+		dpi   float64
+		width string
+		want  float64 // in millimetres
+	}{
+		{96, "10mm", 10},
+		{90, "10mm", 10},
+		{72, "10mm", 10},
+		{72, "1in", 25.4},
+		{72, "2cm", 20},
+		{72, "36pt", 12.7},
+		{72, "72", 25.4},
+		{90, "90px", 25.4},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s at %v dpi", tt.width, tt.dpi), func(t *testing.T) {
+			doc := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="100mm" height="100mm">`+
+				`<rect width="%[1]s" height="%[1]s"/></svg>`, tt.width)
+			s, err := ReadSVG(strings.NewReader(doc), tt.dpi)
+			if err != nil {
+				t.Fatalf("ReadSVG failed: %v", err)
+			}
+			w, h := shapeSize(t, s, tt.dpi)
+			if math.Abs(w-tt.want) > 1e-9 || math.Abs(h-tt.want) > 1e-9 {
+				t.Errorf("rect is %v by %vmm, want %vmm square", w, h, tt.want)
+			}
+		})
+	}
+}
+
+func TestShapePercentages(t *testing.T) {
+	tests := []struct {
+		name          string
+		root          string
+		rect          string
+		width, height float64 // in millimetres
+	}{
+		{"against the viewBox", `width="200mm" height="100mm" viewBox="0 0 20 10"`,
+			`width="50%" height="50%"`, 100, 50},
+		{"against the width and height", `width="200mm" height="100mm"`,
+			`width="50%" height="10%"`, 100, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" %s><rect %s/></svg>`, tt.root, tt.rect)
+			s, err := ReadSVG(strings.NewReader(doc), DefaultDPI)
+			if err != nil {
+				t.Fatalf("ReadSVG failed: %v", err)
+			}
+			w, h := shapeSize(t, s, DefaultDPI)
+			if math.Abs(w-tt.width) > 1e-9 || math.Abs(h-tt.height) > 1e-9 {
+				t.Errorf("rect is %v by %vmm, want %v by %vmm", w, h, tt.width, tt.height)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package svg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattolenik/svg2scad/log"
+	"github.com/mattolenik/svg2scad/svg/ast"
+)
+
+// FillRule decides which parts of a path with several, possibly overlapping, subpaths are inside it.
+type FillRule string
+
+const (
+	NonZero FillRule = "nonzero"
+	EvenOdd FillRule = "evenodd"
+)
+
+func ParseFillRule(s string) (FillRule, error) {
+	switch rule := FillRule(strings.TrimSpace(s)); rule {
+	case NonZero, EvenOdd:
+		return rule, nil
+	default:
+		return "", fmt.Errorf("invalid fill-rule %q", s)
+	}
+}
+
+// Paint is the value of the fill or stroke property.
+type Paint struct {
+	None  bool
+	Color ast.Color // the color to paint with, unless None is set
+	URL   string    // the paint server referenced with url(), such as a gradient, if any. Color is its fallback.
+}
+
+// Style holds the computed values of the presentation properties the converter reads.
+type Style struct {
+	Fill        Paint
+	Stroke      Paint
+	StrokeWidth Length // in the element's user units, unless it has a unit
+	FillRule    FillRule
+	Opacity     float64 // from 0 to 1
+	Display     string  // "none" means the element and its children aren't rendered
+	Visibility  string  // "visible", "hidden" or "collapse"
+	Color       ast.Color
+}
+
+// Displayed reports whether the element is rendered, which its children can't override.
+func (s Style) Displayed() bool {
+	return s.Display != "none"
+}
+
+// Visible reports whether the element is drawn. Unlike display, a child of a hidden group can be visible.
+func (s Style) Visible() bool {
+	return s.Displayed() && s.Visibility == "visible"
+}
+
+// InitialStyle is the style of the root element's parent, which holds the initial value of every property.
+var InitialStyle = Style{
+	Fill:        Paint{Color: namedColors["black"]},
+	Stroke:      Paint{None: true},
+	StrokeWidth: Length{Value: 1},
+	FillRule:    NonZero,
+	Opacity:     1,
+	Display:     "inline",
+	Visibility:  "visible",
+	Color:       namedColors["black"],
+}
+
+// Declarations maps property names to their specified values, e.g. "fill" to "#ff0000".
+type Declarations map[string]string
+
+// ParseDeclarations parses the declarations in a style attribute or CSS rule, e.g. "fill-rule:evenodd;fill:none",
+// into those declared normally and those declared !important. When a property is declared more than once, the
+// last declaration wins.
+func ParseDeclarations(style string) (normal, important Declarations) {
+	normal, important = Declarations{}, Declarations{}
+	for _, decl := range strings.Split(style, ";") {
+		prop, value, found := strings.Cut(decl, ":")
+		prop, value = strings.ToLower(strings.TrimSpace(prop)), strings.TrimSpace(value)
+		if !found || prop == "" || value == "" {
+			continue
+		}
+		if v, ok := strings.CutSuffix(value, "!important"); ok {
+			important[prop] = strings.TrimSpace(v)
+		} else {
+			normal[prop] = value
+		}
+	}
+	return normal, important
+}
+
+// declarations returns the specified properties of the last element of path, which is preceded by its ancestors.
+// They are cascaded from lowest to highest precedence: presentation attributes, the stylesheet's rules, the style
+// attribute, then the stylesheet's !important declarations and the style attribute's.
+func (e *Element) declarations(sheet Stylesheet, path []target) Declarations {
+	decls := Declarations{}
+	for prop, value := range map[string]string{
+		"fill":         e.Fill,
+		"stroke":       e.Stroke,
+		"stroke-width": e.StrokeWidth,
+		"fill-rule":    e.FillRule,
+		"opacity":      e.Opacity,
+		"display":      e.Display,
+		"visibility":   e.Visibility,
+		"color":        e.Color,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			decls[prop] = value
+		}
+	}
+	rules := sheet.matching(path)
+	inline, inlineImportant := ParseDeclarations(e.Style)
+	layers := []Declarations{}
+	for _, r := range rules {
+		layers = append(layers, r.normal)
+	}
+	layers = append(layers, inline)
+	for _, r := range rules {
+		layers = append(layers, r.important)
+	}
+	layers = append(layers, inlineImportant)
+	for _, layer := range layers {
+		for prop, value := range layer {
+			decls[prop] = value
+		}
+	}
+	return decls
+}
+
+// inheritedProperties are the properties that an element takes from its parent when it doesn't specify them.
+// Every property can be inherited explicitly with the value "inherit".
+var inheritedProperties = map[string]bool{
+	"fill":         true,
+	"stroke":       true,
+	"stroke-width": true,
+	"fill-rule":    true,
+	"visibility":   true,
+	"color":        true,
+}
+
+// Compute returns the style of an element with the declarations d, whose parent has the style parent. Values
+// that can't be parsed are ignored with a warning, as CSS does.
+func (d Declarations) Compute(parent Style) Style {
+	style := parent
+	// Properties that aren't inherited start from their initial values
+	style.Opacity = InitialStyle.Opacity
+	style.Display = InitialStyle.Display
+
+	// color comes first, since fill and stroke can refer to it with currentColor
+	for _, prop := range []string{"color", "fill", "stroke", "stroke-width", "fill-rule", "opacity", "display", "visibility"} {
+		value, ok := d[prop]
+		if !ok || value == "inherit" {
+			if ok && !inheritedProperties[prop] {
+				style.inherit(prop, parent)
+			}
+			continue
+		}
+		if err := style.set(prop, value); err != nil {
+			log.Warnf("ignoring %s: %v", prop, err)
+		}
+	}
+	return style
+}
+
+// inherit copies a property that isn't inherited by default from the parent's style.
+func (s *Style) inherit(prop string, parent Style) {
+	switch prop {
+	case "opacity":
+		s.Opacity = parent.Opacity
+	case "display":
+		s.Display = parent.Display
+	}
+}
+
+// set parses the value of a property into the style.
+func (s *Style) set(prop, value string) error {
+	var err error
+	switch prop {
+	case "color":
+		if value != "currentColor" {
+			s.Color, err = ParseColor(value)
+		}
+	case "fill":
+		s.Fill, err = s.parsePaint(value)
+	case "stroke":
+		s.Stroke, err = s.parsePaint(value)
+	case "stroke-width":
+		var l Length
+		if l, err = ParseLength(value); err == nil && l.Value < 0 {
+			err = fmt.Errorf("stroke-width %q must not be negative", value)
+		}
+		if err == nil {
+			s.StrokeWidth = l
+		}
+	case "fill-rule":
+		var rule FillRule
+		if rule, err = ParseFillRule(value); err == nil {
+			s.FillRule = rule
+		}
+	case "opacity":
+		var v float64
+		if v, err = parseOpacity(value); err == nil {
+			s.Opacity = v
+		}
+	case "display":
+		s.Display = value
+	case "visibility":
+		switch value {
+		case "visible", "hidden", "collapse":
+			s.Visibility = value
+		default:
+			err = fmt.Errorf("invalid visibility %q", value)
+		}
+	}
+	return err
+}
+
+// parsePaint parses a fill or stroke: none, currentColor, a color, or a url() reference with an optional
+// fallback color.
+func (s *Style) parsePaint(value string) (Paint, error) {
+	if url, fallback, ok := strings.Cut(value, ")"); ok && strings.HasPrefix(url, "url(") {
+		paint := Paint{URL: strings.Trim(strings.TrimSpace(url[4:]), `"'`)}
+		fallback = strings.TrimSpace(fallback)
+		if fallback == "" {
+			return paint, nil // there is nothing to fall back to, the paint server is all there is
+		}
+		p, err := s.parsePaint(fallback)
+		paint.None, paint.Color = p.None, p.Color
+		return paint, err
+	}
+	switch value {
+	case "none":
+		return Paint{None: true}, nil
+	case "currentColor":
+		return Paint{Color: s.Color}, nil
+	}
+	c, err := ParseColor(value)
+	return Paint{Color: c}, err
+}
+
+// parseOpacity parses an opacity given as a number or a percentage, clamped to the range from 0 to 1.
+func parseOpacity(value string) (float64, error) {
+	if strings.HasSuffix(value, "%") {
+		return parsePercent(value)
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid opacity %q", value)
+	}
+	return clamp(v), nil
+}
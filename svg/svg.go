@@ -6,48 +6,485 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/mattolenik/svg2scad/log"
+	"github.com/mattolenik/svg2scad/svg/ast"
 )
 
 type SVG struct {
-	XMLName    xml.Name `xml:"svg"`
-	Version    string   `xml:"version,attr"`
-	Width      string   `xml:"width,attr"`
-	Height     string   `xml:"height,attr"`
-	Paths      []*Path  `xml:"path"`
-	Transforms []any    `xml:"g"`
-	Filename   string
+	XMLName             xml.Name `xml:"svg"`
+	Version             string   `xml:"version,attr"`
+	Width               string   `xml:"width,attr"`
+	Height              string   `xml:"height,attr"`
+	ViewBox             string   `xml:"viewBox,attr"`
+	PreserveAspectRatio string   `xml:"preserveAspectRatio,attr"`
+	Group
+	Filename string
+
+	index   map[string]*Definition // the elements <use> can refer to, by ID
+	symbols []*Definition
+	uses    []*Use
+}
+
+// Element holds the attributes common to every element the converter reads.
+type Element struct {
+	ID        string `xml:"id,attr"`
+	Label     string `xml:"http://www.inkscape.org/namespaces/inkscape label,attr"`
+	SerifID   string `xml:"http://www.serif.com/ id,attr"` // Affinity Designer's name for the element
+	Class     string `xml:"class,attr"`
+	Style     string `xml:"style,attr"`
+	Transform string `xml:"transform,attr"`
+
+	// Presentation attributes, which the style attribute overrides
+	Fill        string `xml:"fill,attr"`
+	Stroke      string `xml:"stroke,attr"`
+	StrokeWidth string `xml:"stroke-width,attr"`
+	FillRule    string `xml:"fill-rule,attr"`
+	Opacity     string `xml:"opacity,attr"`
+	Display     string `xml:"display,attr"`
+	Visibility  string `xml:"visibility,attr"`
+	Color       string `xml:"color,attr"`
+
+	// Computed is the element's style after inheritance, set when the SVG is read
+	Computed Style `xml:"-"`
+
+	// CTM is the current transformation matrix: the element's own transform combined with those of all its
+	// ancestors. It maps the element's coordinates, e.g. those in a path's D, to the coordinate system of the
+	// root <svg> element.
+	CTM Matrix `xml:"-"`
+}
+
+// Name returns the name the element was given in the editor that made it, if any, otherwise its ID.
+// Editors often set the ID to something generated, e.g. path1234, and keep the name the user chose elsewhere.
+func (e *Element) Name() string {
+	for _, name := range []string{e.Label, e.SerifID} {
+		if strings.TrimSpace(name) != "" {
+			return name
+		}
+	}
+	return e.ID
+}
+
+// Group is a <g> element. The root <svg> element embeds it too, since it can hold the same children.
+type Group struct {
+	Element
+	GroupMode string      `xml:"http://www.inkscape.org/namespaces/inkscape groupmode,attr"` // "layer" for Inkscape layers
+	Paths     []*Path     `xml:"path"`                                                       // after reading, this also holds the group's basic shapes, see convertShapes
+	Groups    []*Group    `xml:"g"`
+	Rects     []*Rect     `xml:"rect"`
+	Circles   []*Circle   `xml:"circle"`
+	Ellipses  []*Ellipse  `xml:"ellipse"`
+	Lines     []*Line     `xml:"line"`
+	Polylines []*Polyline `xml:"polyline"`
+	Polygons  []*Polygon  `xml:"polygon"`
+	Uses      []*Use      `xml:"use"`
+	Symbols   []*Symbol   `xml:"symbol"` // only drawn through <use> elements
+
+	StyleSheets []string `xml:"style"` // the contents of <style> elements, which apply to the whole document
+	Defs        []*Defs  `xml:"defs"`
+}
+
+// Defs is a <defs> element, which holds content that isn't drawn directly.
+type Defs struct {
+	Group
+}
+
+// styleSheet parses the <style> elements in the group and its descendants, in document order per group.
+func (g *Group) styleSheet() Stylesheet {
+	css := append([]string{}, g.StyleSheets...)
+	sheet := Stylesheet{}
+	for _, text := range css {
+		sheet = append(sheet, ParseStylesheet(text)...)
+	}
+	for _, defs := range g.Defs {
+		sheet = append(sheet, defs.styleSheet()...)
+	}
+	for _, symbol := range g.Symbols {
+		sheet = append(sheet, symbol.styleSheet()...)
+	}
+	for _, child := range g.Groups {
+		sheet = append(sheet, child.styleSheet()...)
+	}
+	for i := range sheet {
+		sheet[i].order = i
+	}
+	return sheet
 }
 
 type Path struct {
-	ID    string `xml:"id,attr"`
-	D     string `xml:"d,attr"`
-	Style string `xml:"style,attr"`
+	XMLName xml.Name // "path", or the name of the basic shape the path was converted from
+	Element
+	D string `xml:"d,attr"`
+
+	tree *ast.Path // set for basic shapes, which are converted straight to an AST rather than to D
+}
+
+// Parse returns the AST of the path data, or nil if there is none. Path data with an error in it is drawn up to
+// the error, which is logged.
+func (p *Path) Parse() (*ast.Path, error) {
+	if p.tree != nil {
+		return p.tree, nil
+	}
+	if strings.TrimSpace(p.D) == "" {
+		log.Debugf("path %q has no path data", p.ID)
+		return nil, nil
+	}
+	tree, err := ast.Parse(p.ID, []byte(p.D))
+	if err != nil {
+		return nil, err
+	}
+	path := tree.(*ast.Path)
+	if path.Unparsed != "" {
+		log.Warnf("path %q has invalid path data, ignoring it from %q on", p.ID, abbreviate(path.Unparsed, 20))
+	}
+	return path, nil
+}
+
+// abbreviate shortens s to at most n runes, ending it with an ellipsis if it was longer.
+func abbreviate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// Contents returns the paths and <use> elements in the group and its descendants, in document order per group:
+// a group's own paths come before those of its <defs> and then its child groups. Hidden ones, and those in
+// <defs>, are skipped unless includeHidden is set. The contents of symbols are never included, since they are
+// only drawn through <use> elements.
+func (g *Group) Contents(includeHidden bool) ([]*Path, []*Use) {
+	paths, uses := []*Path{}, []*Use{}
+	for _, path := range g.Paths {
+		if !includeHidden && !path.Computed.Visible() {
+			log.Debugf("skipping hidden %s %q", path.XMLName.Local, path.ID)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	for _, use := range g.Uses {
+		// The content a use draws inherits its visibility, so a hidden use is treated like a hidden path. Content
+		// that sets visibility="visible" itself would still be drawn by a browser, but that is rare.
+		if !includeHidden && !use.Computed.Visible() {
+			log.Debugf("skipping hidden use %q", use.ID)
+			continue
+		}
+		uses = append(uses, use)
+	}
+	children := []*Group{}
+	for _, defs := range g.Defs {
+		if !includeHidden {
+			if p, u := defs.Contents(true); len(p) > 0 || len(u) > 0 {
+				log.Debugf("skipping the contents of <defs>, they are only drawn where they are used")
+			}
+			continue
+		}
+		children = append(children, &defs.Group)
+	}
+	for _, child := range g.Groups {
+		if !includeHidden && !child.Computed.Displayed() {
+			log.Debugf("skipping group %q and its contents, since it isn't displayed", child.ID)
+			continue
+		}
+		children = append(children, child)
+	}
+	for _, child := range children {
+		p, u := child.Contents(includeHidden)
+		paths, uses = append(paths, p...), append(uses, u...)
+	}
+	return paths, uses
+}
+
+// IsLayer reports whether the group is an Inkscape layer.
+func (g *Group) IsLayer() bool {
+	return g.GroupMode == "layer"
+}
+
+// Layers returns the outermost Inkscape layers in the group, in document order. Layers nested in them can be
+// found by calling Layers on each one. Layers that aren't displayed, or are in a group that isn't, are skipped
+// unless includeHidden is set.
+func (g *Group) Layers(includeHidden bool) []*Group {
+	layers := []*Group{}
+	for _, child := range g.Groups {
+		if !includeHidden && !child.Computed.Displayed() {
+			continue
+		}
+		if child.IsLayer() {
+			layers = append(layers, child)
+		} else {
+			layers = append(layers, child.Layers(includeHidden)...)
+		}
+	}
+	return layers
+}
+
+// convertShapes converts the basic shapes in the group and its descendants to paths, which are appended to the
+// Paths of the group that contains them. Percentage lengths are relative to vp, or to the viewBox of a <symbol>
+// that has one. A shape that can't be converted is skipped with a warning. The <use> elements are given the
+// viewport too, for their own lengths.
+func (g *Group) convertShapes(vp Viewport) {
+	for _, use := range g.Uses {
+		use.viewport = vp
+	}
+	shapes := []Shape{}
+	for _, s := range g.Rects {
+		shapes = append(shapes, s)
+	}
+	for _, s := range g.Circles {
+		shapes = append(shapes, s)
+	}
+	for _, s := range g.Ellipses {
+		shapes = append(shapes, s)
+	}
+	for _, s := range g.Lines {
+		shapes = append(shapes, s)
+	}
+	for _, s := range g.Polylines {
+		shapes = append(shapes, s)
+	}
+	for _, s := range g.Polygons {
+		shapes = append(shapes, s)
+	}
+	for _, shape := range shapes {
+		path, err := shape.ToPath(vp)
+		if err != nil {
+			log.Warnf("skipping shape: %v", err)
+			continue
+		}
+		if path != nil {
+			g.Paths = append(g.Paths, path)
+		}
+	}
+	for _, defs := range g.Defs {
+		defs.convertShapes(vp)
+	}
+	for _, symbol := range g.Symbols {
+		symbol.convertShapes(viewBoxViewport(symbol.ViewBox, vp))
+	}
+	for _, child := range g.Groups {
+		child.convertShapes(vp)
+	}
+}
+
+// viewBoxViewport returns the size of the viewBox, or def if there is none.
+func viewBoxViewport(viewBox string, def Viewport) Viewport {
+	if strings.TrimSpace(viewBox) == "" {
+		return def
+	}
+	vb, err := parseViewBox(viewBox)
+	if err != nil {
+		return def // reported when the viewBox is used
+	}
+	return Viewport{Width: vb[2], Height: vb[3], DPI: def.DPI}
+}
+
+// Viewport returns the size of the root element's viewport in user units: its viewBox if it has one, otherwise
+// its width and height converted at the given DPI. When those are missing or percentages, the size is unknown
+// and left zero.
+func (s *SVG) Viewport(dpi float64) Viewport {
+	vp := Viewport{DPI: dpi}
+	if w, err := parseUserUnits(s.Width, 0, dpi); err == nil {
+		vp.Width = w
+	}
+	if h, err := parseUserUnits(s.Height, 0, dpi); err == nil {
+		vp.Height = h
+	}
+	return viewBoxViewport(s.ViewBox, vp)
+}
+
+// inherited is the state a group passes down to its children.
+type inherited struct {
+	ctm       Matrix
+	style     Style
+	sheet     Stylesheet
+	ancestors []target // from the root down
+}
+
+// resolve sets the CTM of every path in the group, and the computed style of every element, given the state
+// inherited from the group's parent.
+func (g *Group) resolve(tag string, parent inherited) error {
+	state, err := g.Element.resolve(tag, parent)
+	if err != nil {
+		return fmt.Errorf("group %q: %w", g.ID, err)
+	}
+	for _, path := range g.Paths {
+		if _, err := path.Element.resolve(path.XMLName.Local, state); err != nil {
+			return fmt.Errorf("%s %q: %w", path.XMLName.Local, path.ID, err)
+		}
+	}
+	for _, use := range g.Uses {
+		if _, err := use.Element.resolve("use", state); err != nil {
+			return fmt.Errorf("use %q: %w", use.ID, err)
+		}
+	}
+	for _, defs := range g.Defs {
+		if err := defs.resolve("defs", state); err != nil {
+			return err
+		}
+	}
+	for _, symbol := range g.Symbols {
+		if err := symbol.resolve("symbol", state); err != nil {
+			return err
+		}
+	}
+	for _, child := range g.Groups {
+		if err := child.resolve("g", state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve combines the element's own transform and style with those it inherits, and sets its computed style.
+func (e *Element) resolve(tag string, parent inherited) (inherited, error) {
+	m, err := ParseTransform(e.Transform)
+	if err != nil {
+		return parent, err
+	}
+	path := append(append([]target{}, parent.ancestors...), target{tag: tag, element: e})
+	e.Computed = e.declarations(parent.sheet, path).Compute(parent.style)
+	e.CTM = parent.ctm.Multiply(m)
+	return inherited{ctm: e.CTM, style: e.Computed, sheet: parent.sheet, ancestors: path}, nil
+}
+
+// ViewportTransform returns the transform from the root element's user units to millimetres. It applies the
+// viewBox and preserveAspectRatio mapping onto the viewport given by width and height. Pixels and unitless
+// lengths are converted at the given DPI.
+func (s *SVG) ViewportTransform(dpi float64) (Matrix, error) {
+	pxToMM := mmPerInch / dpi
+	if strings.TrimSpace(s.ViewBox) == "" {
+		// Without a viewBox, user units are px no matter what units the viewport size is given in
+		return Scale(pxToMM, pxToMM), nil
+	}
+	vb, err := parseViewBox(s.ViewBox)
+	if err != nil {
+		return Identity, err
+	}
+	width, err := viewportLength(s.Width, vb[2], dpi)
+	if err != nil {
+		return Identity, fmt.Errorf("invalid width: %w", err)
+	}
+	height, err := viewportLength(s.Height, vb[3], dpi)
+	if err != nil {
+		return Identity, fmt.Errorf("invalid height: %w", err)
+	}
+	return viewBoxTransform(vb, s.PreserveAspectRatio, width, height)
+}
+
+func parseViewBox(viewBox string) ([]float64, error) {
+	vb, err := parseNumberList(viewBox)
+	if err != nil || len(vb) != 4 {
+		return nil, fmt.Errorf("invalid viewBox %q", viewBox)
+	}
+	if vb[2] <= 0 || vb[3] <= 0 {
+		return nil, fmt.Errorf("viewBox %q must have a positive width and height", viewBox)
+	}
+	return vb, nil
+}
+
+// viewBoxTransform returns the transform that maps the viewBox vb onto a viewport of the given size, following
+// the preserveAspectRatio attribute.
+func viewBoxTransform(vb []float64, preserveAspectRatio string, width, height float64) (Matrix, error) {
+	sx, sy := width/vb[2], height/vb[3]
+	align, meetOrSlice, err := parsePreserveAspectRatio(preserveAspectRatio)
+	if err != nil {
+		return Identity, err
+	}
+	if align == "none" {
+		return Scale(sx, sy).Multiply(Translate(-vb[0], -vb[1])), nil
+	}
+	scale := min(sx, sy)
+	if meetOrSlice == "slice" {
+		scale = max(sx, sy)
+	}
+	tx := alignOffset(align[1:4], width-vb[2]*scale)
+	ty := alignOffset(align[5:8], height-vb[3]*scale)
+	return Translate(tx, ty).Multiply(Scale(scale, scale)).Multiply(Translate(-vb[0], -vb[1])), nil
+}
+
+// viewportLength converts a width or height attribute to millimetres. When it is missing or a percentage, it is
+// taken relative to the viewBox size in px, since a standalone file has no enclosing viewport.
+func viewportLength(attr string, viewBoxSize, dpi float64) (float64, error) {
+	if strings.TrimSpace(attr) == "" {
+		attr = "100%"
+	}
+	l, err := ParseLength(attr)
+	if err != nil {
+		return 0, err
+	}
+	if l.IsPercent() {
+		l = Length{Value: viewBoxSize * l.Value / 100}
+	}
+	return l.Millimetres(dpi)
+}
+
+func parsePreserveAspectRatio(attr string) (align, meetOrSlice string, err error) {
+	fields := strings.Fields(attr)
+	if len(fields) > 0 && fields[0] == "defer" {
+		fields = fields[1:]
+	}
+	align, meetOrSlice = "xMidYMid", "meet"
+	if len(fields) > 0 {
+		align = fields[0]
+	}
+	if len(fields) > 1 {
+		meetOrSlice = fields[1]
+	}
+	validAlign := align == "none" ||
+		(len(align) == 8 && align[0] == 'x' && align[4] == 'Y' && isAlignKeyword(align[1:4]) && isAlignKeyword(align[5:8]))
+	if !validAlign || (meetOrSlice != "meet" && meetOrSlice != "slice") || len(fields) > 2 {
+		return "", "", fmt.Errorf("invalid preserveAspectRatio %q", attr)
+	}
+	return align, meetOrSlice, nil
 }
 
-func ReadSVGFromFile(path string) (*SVG, error) {
+func isAlignKeyword(s string) bool {
+	return s == "Min" || s == "Mid" || s == "Max"
+}
+
+// alignOffset positions content within the extra space according to a Min, Mid or Max alignment keyword.
+func alignOffset(keyword string, extra float64) float64 {
+	switch keyword {
+	case "Mid":
+		return extra / 2
+	case "Max":
+		return extra
+	default:
+		return 0
+	}
+}
+
+// ReadSVGFromFile reads an SVG file, see ReadSVG.
+func ReadSVGFromFile(path string, dpi float64) (*SVG, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	svg, err := ReadSVG(file)
+	svg, err := ReadSVG(file, dpi)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read SVG: %w", err)
 	}
-	if len(svg.Transforms) > 0 {
-		return nil, fmt.Errorf("the <g> element (transform) is not yet implemented, please flatten transforms when exporting your SVG")
-	}
 	svg.Filename = filepath.Base(file.Name())
 
 	return svg, nil
 }
 
-func ReadSVG(r io.Reader) (*SVG, error) {
+// ReadSVG reads an SVG document, converting its basic shapes to paths. Their absolute lengths, e.g. "10mm", are
+// converted to user units at the given DPI, the one px are converted to millimetres at.
+func ReadSVG(r io.Reader, dpi float64) (*SVG, error) {
 	var svg SVG
 	err := xml.NewDecoder(r).Decode(&svg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode SVG: %w", err)
 	}
+	svg.convertShapes(svg.Viewport(dpi))
+	root := inherited{ctm: Identity, style: InitialStyle, sheet: svg.styleSheet()}
+	if err := svg.resolve("svg", root); err != nil {
+		return nil, fmt.Errorf("failed to resolve transforms and styles: %w", err)
+	}
+	svg.index = map[string]*Definition{}
+	svg.indexGroup("svg", &svg.Group)
 	return &svg, nil
 }
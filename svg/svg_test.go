@@ -0,0 +1,43 @@
+package svg
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestContents(t *testing.T) {
+	file, err := os.Open("../test/hidden.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	s, err := ReadSVG(file, DefaultDPI)
+	if err != nil {
+		t.Fatalf("ReadSVG failed: %v", err)
+	}
+	tests := []struct {
+		includeHidden bool
+		paths, uses   []string
+	}{
+		{false, []string{"shown", "visible-child"}, []string{"copy"}},
+		{true, []string{"shown", "guide", "construction", "template", "in-hidden-layer", "invisible-child",
+			"visible-child"}, []string{"ghost", "copy"}},
+	}
+	for _, tt := range tests {
+		paths, uses := s.Contents(tt.includeHidden)
+		pathIDs, useIDs := []string{}, []string{}
+		for _, p := range paths {
+			pathIDs = append(pathIDs, p.ID)
+		}
+		for _, u := range uses {
+			useIDs = append(useIDs, u.ID)
+		}
+		if !reflect.DeepEqual(pathIDs, tt.paths) {
+			t.Errorf("Contents(%v) paths = %v, want %v", tt.includeHidden, pathIDs, tt.paths)
+		}
+		if !reflect.DeepEqual(useIDs, tt.uses) {
+			t.Errorf("Contents(%v) uses = %v, want %v", tt.includeHidden, useIDs, tt.uses)
+		}
+	}
+}
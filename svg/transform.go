@@ -0,0 +1,185 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matrix is a 2D affine transform in SVG's [a b c d e f] order, which maps (x, y) to (a*x + c*y + e, b*x + d*y + f).
+type Matrix [6]float64
+
+var Identity = Matrix{1, 0, 0, 1, 0, 0}
+
+// Multiply returns m × n, the transform that applies n first and then m.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+// Apply transforms the point (x, y).
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+}
+
+// Invert returns the inverse of m, the transform that undoes it. It fails if m collapses the plane onto a line
+// or a point, e.g. scale(0).
+func (m Matrix) Invert() (Matrix, error) {
+	det := m[0]*m[3] - m[1]*m[2]
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Identity, fmt.Errorf("transform %v can't be inverted", m)
+	}
+	return Matrix{
+		m[3] / det,
+		-m[1] / det,
+		-m[2] / det,
+		m[0] / det,
+		(m[2]*m[5] - m[3]*m[4]) / det,
+		(m[1]*m[4] - m[0]*m[5]) / det,
+	}, nil
+}
+
+// Linear returns m without its translation.
+func (m Matrix) Linear() Matrix {
+	return Matrix{m[0], m[1], m[2], m[3], 0, 0}
+}
+
+// MaxScale returns the most that m stretches any distance by, whatever its direction.
+func (m Matrix) MaxScale() float64 {
+	// The largest singular value of the linear part
+	sum := m[0]*m[0] + m[1]*m[1] + m[2]*m[2] + m[3]*m[3]
+	det := m[0]*m[3] - m[1]*m[2]
+	return math.Sqrt((sum + math.Sqrt(max(sum*sum-4*det*det, 0))) / 2)
+}
+
+func Translate(tx, ty float64) Matrix {
+	return Matrix{1, 0, 0, 1, tx, ty}
+}
+
+func Scale(sx, sy float64) Matrix {
+	return Matrix{sx, 0, 0, sy, 0, 0}
+}
+
+// Rotate returns a rotation by the given angle in degrees about the origin.
+func Rotate(deg float64) Matrix {
+	sin, cos := math.Sincos(deg * math.Pi / 180)
+	return Matrix{cos, sin, -sin, cos, 0, 0}
+}
+
+func SkewX(deg float64) Matrix {
+	return Matrix{1, 0, math.Tan(deg * math.Pi / 180), 1, 0, 0}
+}
+
+func SkewY(deg float64) Matrix {
+	return Matrix{1, math.Tan(deg * math.Pi / 180), 0, 1, 0, 0}
+}
+
+var (
+	transformFuncRegex = regexp.MustCompile(`^[\s,]*([a-zA-Z]+)\s*\(([^)]*)\)`)
+	numberRegex        = regexp.MustCompile(`[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?`)
+)
+
+// ParseTransform parses the value of a transform attribute, e.g. "translate(10, 20) rotate(45)". The functions
+// are combined left to right, as the spec requires. An empty string yields the identity transform.
+func ParseTransform(transform string) (Matrix, error) {
+	result := Identity
+	rest := transform
+	for strings.Trim(rest, " \t\r\n,") != "" {
+		match := transformFuncRegex.FindStringSubmatch(rest)
+		if match == nil {
+			return Identity, fmt.Errorf("invalid transform %q at %q", transform, rest)
+		}
+		rest = rest[len(match[0]):]
+
+		args, err := parseNumberList(match[2])
+		if err != nil {
+			return Identity, fmt.Errorf("invalid arguments in transform %q: %w", match[0], err)
+		}
+		m, err := transformFunc(match[1], args)
+		if err != nil {
+			return Identity, fmt.Errorf("invalid transform %q: %w", strings.TrimLeft(match[0], " \t\r\n,"), err)
+		}
+		result = result.Multiply(m)
+	}
+	return result, nil
+}
+
+// parseNumberList parses a comma and/or whitespace separated list of numbers.
+func parseNumberList(s string) ([]float64, error) {
+	if leftover := strings.Trim(numberRegex.ReplaceAllString(s, ""), " \t\r\n,"); leftover != "" {
+		return nil, fmt.Errorf("unexpected %q", leftover)
+	}
+	strs := numberRegex.FindAllString(s, -1)
+	args := make([]float64, len(strs))
+	for i, str := range strs {
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+func transformFunc(name string, args []float64) (Matrix, error) {
+	argCountErr := func(counts ...int) error {
+		for _, c := range counts {
+			if len(args) == c {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s takes %v arguments, got %d", name, counts, len(args))
+	}
+	switch name {
+	case "matrix":
+		if err := argCountErr(6); err != nil {
+			return Identity, err
+		}
+		return Matrix(args), nil
+	case "translate":
+		if err := argCountErr(1, 2); err != nil {
+			return Identity, err
+		}
+		if len(args) == 1 {
+			return Translate(args[0], 0), nil
+		}
+		return Translate(args[0], args[1]), nil
+	case "scale":
+		if err := argCountErr(1, 2); err != nil {
+			return Identity, err
+		}
+		if len(args) == 1 {
+			return Scale(args[0], args[0]), nil
+		}
+		return Scale(args[0], args[1]), nil
+	case "rotate":
+		if err := argCountErr(1, 3); err != nil {
+			return Identity, err
+		}
+		if len(args) == 1 {
+			return Rotate(args[0]), nil
+		}
+		// rotate(a, cx, cy) rotates about the point (cx, cy)
+		return Translate(args[1], args[2]).Multiply(Rotate(args[0])).Multiply(Translate(-args[1], -args[2])), nil
+	case "skewX":
+		if err := argCountErr(1); err != nil {
+			return Identity, err
+		}
+		return SkewX(args[0]), nil
+	case "skewY":
+		if err := argCountErr(1); err != nil {
+			return Identity, err
+		}
+		return SkewY(args[0]), nil
+	default:
+		return Identity, fmt.Errorf("unknown transform function %q", name)
+	}
+}
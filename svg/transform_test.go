@@ -0,0 +1,63 @@
+package svg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		transform string
+		want      Matrix
+	}{
+		{"", Identity},
+		{"  ", Identity},
+		{"matrix(1 2 3 4 5 6)", Matrix{1, 2, 3, 4, 5, 6}},
+		{"translate(10)", Matrix{1, 0, 0, 1, 10, 0}},
+		{"translate(10, 20)", Matrix{1, 0, 0, 1, 10, 20}},
+		{"scale(2)", Matrix{2, 0, 0, 2, 0, 0}},
+		{"scale(2 -3)", Matrix{2, 0, 0, -3, 0, 0}},
+		{"rotate(90)", Matrix{0, 1, -1, 0, 0, 0}},
+		{"rotate(90 10 0)", Matrix{0, 1, -1, 0, 10, -10}},
+		{"skewX(45)", Matrix{1, 0, 1, 1, 0, 0}},
+		{"skewY(45)", Matrix{1, 1, 0, 1, 0, 0}},
+		{"scale(.5e1)", Matrix{5, 0, 0, 5, 0, 0}},
+		// Functions apply right to left to a point, so the translation is scaled here
+		{"scale(2) translate(10,20)", Matrix{2, 0, 0, 2, 20, 40}},
+		{"translate(10,20) scale(2)", Matrix{2, 0, 0, 2, 10, 20}},
+		{"translate(10,20),scale(2)", Matrix{2, 0, 0, 2, 10, 20}},
+		{"\n\ttranslate( 10 , 20 )\n", Matrix{1, 0, 0, 1, 10, 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.transform, func(t *testing.T) {
+			got, err := ParseTransform(tt.transform)
+			if err != nil {
+				t.Fatalf("ParseTransform(%q) failed: %v", tt.transform, err)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-12 {
+					t.Fatalf("ParseTransform(%q) = %v, want %v", tt.transform, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTransformErrors(t *testing.T) {
+	for _, transform := range []string{
+		"translate",
+		"translate(1 2 3)",
+		"matrix(1 2 3)",
+		"rotate(1 2)",
+		"skewX()",
+		"spin(45)",
+		"scale(2) junk",
+		"scale(2px)",
+	} {
+		t.Run(transform, func(t *testing.T) {
+			if m, err := ParseTransform(transform); err == nil {
+				t.Errorf("ParseTransform(%q) = %v, want an error", transform, m)
+			}
+		})
+	}
+}
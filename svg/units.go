@@ -0,0 +1,135 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultDPI is the CSS reference resolution, used by Inkscape since 0.92 and by browsers. Older Inkscape
+// versions used 90 and Illustrator uses 72.
+const DefaultDPI = 96.0
+
+const mmPerInch = 25.4
+
+var lengthRegex = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?)\s*(mm|cm|in|pt|pc|px|%)?$`)
+
+// Length is a parsed SVG length such as "50mm" or "100%".
+type Length struct {
+	Value float64
+	Unit  string // empty for unitless lengths, which are user units (px)
+}
+
+func ParseLength(s string) (Length, error) {
+	match := lengthRegex.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return Length{}, fmt.Errorf("invalid length %q", s)
+	}
+	v, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return Length{}, fmt.Errorf("invalid length %q: %w", s, err)
+	}
+	return Length{Value: v, Unit: match[2]}, nil
+}
+
+func (l Length) IsPercent() bool {
+	return l.Unit == "%"
+}
+
+// Millimetres converts the length to millimetres, treating px and unitless values as 1/dpi of an inch.
+// Percentages have no absolute size and must be resolved by the caller.
+func (l Length) Millimetres(dpi float64) (float64, error) {
+	switch l.Unit {
+	case "mm":
+		return l.Value, nil
+	case "cm":
+		return l.Value * 10, nil
+	case "in":
+		return l.Value * mmPerInch, nil
+	case "pt":
+		return l.Value * mmPerInch / 72, nil
+	case "pc":
+		return l.Value * mmPerInch / 6, nil
+	case "%":
+		return 0, fmt.Errorf("cannot convert percentage length %v%% to millimetres", l.Value)
+	default:
+		return l.Value * mmPerInch / dpi, nil
+	}
+}
+
+// UserUnits converts the length to user units (px). Absolute units are converted at the given DPI, the same one
+// px are later converted to millimetres at, so that they come out at their real size. Percentages are not
+// supported.
+func (l Length) UserUnits(dpi float64) (float64, error) {
+	if l.IsPercent() {
+		return 0, fmt.Errorf("percentage lengths are not supported here")
+	}
+	mm, err := l.Millimetres(dpi)
+	if err != nil {
+		return 0, err
+	}
+	return mm * dpi / mmPerInch, nil
+}
+
+// parseUserUnits parses a length attribute into user units at the given DPI, with an empty attribute giving def.
+func parseUserUnits(attr string, def, dpi float64) (float64, error) {
+	if strings.TrimSpace(attr) == "" {
+		return def, nil
+	}
+	l, err := ParseLength(attr)
+	if err != nil {
+		return 0, err
+	}
+	return l.UserUnits(dpi)
+}
+
+// Viewport is the size, in user units, of the area that percentage lengths are relative to. A zero size means
+// it isn't known, e.g. for a root <svg> element with neither a viewBox nor an absolute width and height. DPI is
+// the resolution absolute lengths are converted to user units at.
+type Viewport struct {
+	Width, Height float64
+	DPI           float64
+}
+
+// axis is the dimension of the viewport a percentage length is relative to.
+type axis int
+
+const (
+	horizontal axis = iota
+	vertical
+	diagonal // lengths that are neither, e.g. a circle's radius
+)
+
+// reference returns the length that 100% along the axis is.
+func (v Viewport) reference(a axis) float64 {
+	switch a {
+	case horizontal:
+		return v.Width
+	case vertical:
+		return v.Height
+	default:
+		return math.Hypot(v.Width, v.Height) / math.Sqrt2
+	}
+}
+
+// parseUserUnits parses a length attribute into user units, like the function of the same name, but resolves
+// percentages against the viewport along the given axis.
+func (v Viewport) parseUserUnits(attr string, def float64, a axis) (float64, error) {
+	if strings.TrimSpace(attr) == "" {
+		return def, nil
+	}
+	l, err := ParseLength(attr)
+	if err != nil {
+		return 0, err
+	}
+	if !l.IsPercent() {
+		return l.UserUnits(v.DPI)
+	}
+	ref := v.reference(a)
+	if ref <= 0 {
+		return 0, fmt.Errorf("percentage length %q needs a viewBox, or an absolute width and height, to be relative to", attr)
+	}
+	return ref * l.Value / 100, nil
+}
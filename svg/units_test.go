@@ -0,0 +1,49 @@
+package svg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMillimetres(t *testing.T) {
+	tests := []struct {
+		length string
+		dpi    float64
+		want   float64
+	}{
+		{"12.5mm", 96, 12.5},
+		{"2cm", 96, 20},
+		{"1in", 96, 25.4},
+		{"72pt", 96, 25.4},
+		{"6pc", 96, 25.4},
+		{"96px", 96, 25.4},
+		{"96", 96, 25.4},
+		{"72", 72, 25.4},
+		{"-1e1mm", 96, -10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.length, func(t *testing.T) {
+			l, err := ParseLength(tt.length)
+			if err != nil {
+				t.Fatalf("ParseLength(%q) failed: %v", tt.length, err)
+			}
+			got, err := l.Millimetres(tt.dpi)
+			if err != nil {
+				t.Fatalf("Millimetres(%v) failed: %v", tt.dpi, err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("%q at %v dpi is %vmm, want %vmm", tt.length, tt.dpi, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMillimetresPercentage(t *testing.T) {
+	l, err := ParseLength("50%")
+	if err != nil {
+		t.Fatalf("ParseLength failed: %v", err)
+	}
+	if _, err := l.Millimetres(DefaultDPI); err == nil {
+		t.Error("Millimetres of a percentage succeeded, want an error")
+	}
+}
@@ -0,0 +1,172 @@
+package svg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Use is a <use> element, which draws a copy of another element in the same file.
+type Use struct {
+	Element
+	Href      string `xml:"href,attr"`
+	XLinkHref string `xml:"http://www.w3.org/1999/xlink href,attr"` // SVG 1.1's href, which SVG 2 replaces
+	X         string `xml:"x,attr"`
+	Y         string `xml:"y,attr"`
+	Width     string `xml:"width,attr"`
+	Height    string `xml:"height,attr"`
+
+	viewport Viewport // the one the use is in, set when the SVG is read
+}
+
+// Ref returns the ID of the element the use refers to. Only references to elements in the same file, such as
+// "#logo", are supported.
+func (u *Use) Ref() (string, error) {
+	href := u.Href
+	if href == "" {
+		href = u.XLinkHref
+	}
+	id, local := strings.CutPrefix(strings.TrimSpace(href), "#")
+	if !local || id == "" {
+		return "", fmt.Errorf("use %q refers to %q, only elements in the same file such as #logo can be used", u.ID, href)
+	}
+	return id, nil
+}
+
+// Placement returns the transform the use applies to the element it refers to, which maps the coordinate system
+// of the element's parent to that of the use. It is the offset given by x and y, and for symbols with a viewBox,
+// the mapping of the viewBox onto the use's width and height. Percentages are relative to the viewport the use is
+// in.
+func (u *Use) Placement(d *Definition) (Matrix, error) {
+	x, err := u.viewport.parseUserUnits(u.X, 0, horizontal)
+	if err != nil {
+		return Identity, fmt.Errorf("invalid x: %w", err)
+	}
+	y, err := u.viewport.parseUserUnits(u.Y, 0, vertical)
+	if err != nil {
+		return Identity, fmt.Errorf("invalid y: %w", err)
+	}
+	m := Translate(x, y)
+	if d.symbol == nil || strings.TrimSpace(d.symbol.ViewBox) == "" {
+		return m, nil
+	}
+	vb, err := parseViewBox(d.symbol.ViewBox)
+	if err != nil {
+		return Identity, err
+	}
+	// Without a width or height, the symbol is drawn at the size of its viewBox
+	width, err := u.viewport.parseUserUnits(u.Width, vb[2], horizontal)
+	if err != nil {
+		return Identity, fmt.Errorf("invalid width: %w", err)
+	}
+	height, err := u.viewport.parseUserUnits(u.Height, vb[3], vertical)
+	if err != nil {
+		return Identity, fmt.Errorf("invalid height: %w", err)
+	}
+	viewBox, err := viewBoxTransform(vb, d.symbol.PreserveAspectRatio, width, height)
+	if err != nil {
+		return Identity, err
+	}
+	return m.Multiply(viewBox), nil
+}
+
+// Symbol is a <symbol> element, a template that is only drawn through <use> elements.
+type Symbol struct {
+	Group
+	ViewBox             string `xml:"viewBox,attr"`
+	PreserveAspectRatio string `xml:"preserveAspectRatio,attr"`
+}
+
+// Definition is an element that <use> elements can refer to: a symbol, a group, a path or basic shape, or
+// another <use>.
+type Definition struct {
+	*Element
+	Tag string
+
+	group  *Group // for groups and symbols
+	symbol *Symbol
+	path   *Path
+	use    *Use
+}
+
+func (d *Definition) IsSymbol() bool {
+	return d.symbol != nil
+}
+
+// Contents returns the paths and <use> elements the definition draws, see Group.Contents.
+func (d *Definition) Contents(includeHidden bool) ([]*Path, []*Use) {
+	if !includeHidden && !d.Computed.Displayed() {
+		return nil, nil
+	}
+	switch {
+	case d.group != nil:
+		return d.group.Contents(includeHidden)
+	case d.path != nil:
+		if !includeHidden && !d.path.Computed.Visible() {
+			return nil, nil
+		}
+		return []*Path{d.path}, nil
+	default:
+		return nil, []*Use{d.use}
+	}
+}
+
+// Local returns the transform from the coordinate system of e, an element the definition draws, to that of the
+// definition's parent. This is the coordinate system a <use> places the definition in.
+func (d *Definition) Local(e *Element) (Matrix, error) {
+	own, err := ParseTransform(d.Transform)
+	if err != nil {
+		return Identity, err
+	}
+	inverse, err := d.CTM.Invert()
+	if err != nil {
+		return Identity, fmt.Errorf("%s %q: %w", d.Tag, d.ID, err)
+	}
+	return own.Multiply(inverse).Multiply(e.CTM), nil
+}
+
+// Lookup returns the element with the given ID that a <use> can refer to.
+func (s *SVG) Lookup(id string) (*Definition, bool) {
+	d, ok := s.index[id]
+	return d, ok
+}
+
+// AllSymbols returns every <symbol> in the document, in document order.
+func (s *SVG) AllSymbols() []*Definition {
+	return s.symbols
+}
+
+// AllUses returns every <use> in the document, including hidden ones and those in <defs> and symbols.
+func (s *SVG) AllUses() []*Use {
+	return s.uses
+}
+
+// indexGroup adds the group and the elements in it, at any depth, to the index of elements <use> can refer to.
+func (s *SVG) indexGroup(tag string, g *Group) {
+	s.define(&Definition{Element: &g.Element, Tag: tag, group: g})
+	for _, path := range g.Paths {
+		s.define(&Definition{Element: &path.Element, Tag: path.XMLName.Local, path: path})
+	}
+	for _, use := range g.Uses {
+		s.define(&Definition{Element: &use.Element, Tag: "use", use: use})
+		s.uses = append(s.uses, use)
+	}
+	for _, child := range g.Groups {
+		s.indexGroup("g", child)
+	}
+	for _, defs := range g.Defs {
+		s.indexGroup("defs", &defs.Group)
+	}
+	for _, symbol := range g.Symbols {
+		d := &Definition{Element: &symbol.Element, Tag: "symbol", group: &symbol.Group, symbol: symbol}
+		s.define(d)
+		s.symbols = append(s.symbols, d)
+		s.indexGroup("symbol", &symbol.Group)
+	}
+}
+
+// define adds d to the index. When several elements have the same ID, references go to the first one.
+func (s *SVG) define(d *Definition) {
+	if _, exists := s.index[d.ID]; d.ID != "" && !exists {
+		s.index[d.ID] = d
+	}
+}